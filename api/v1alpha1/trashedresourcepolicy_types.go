@@ -0,0 +1,154 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyLabel is set on every TrashedResource captured by a TrashedResourcePolicy, with the policy name.
+const PolicyLabel = "mox.app.br/policy"
+
+// TrashedAction is an action over an object that can be captured.
+// +kubebuilder:validation:Enum=delete;update
+type TrashedAction string
+
+const (
+	// ActionDelete captures the object when it is deleted.
+	ActionDelete TrashedAction = "delete"
+	// ActionUpdate captures the previous state of the object when it is updated.
+	ActionUpdate TrashedAction = "update"
+)
+
+// PolicyKind identifies a kind to capture.
+type PolicyKind struct {
+	// Group of the kind, empty for the core group (eg. apps, cert-manager.io).
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version of the kind. When empty the preferred version served by the cluster is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind name (eg. Deployment, Certificate).
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// String returns the kind in the kind.group/version form accepted by kindsToObserve.
+func (k PolicyKind) String() string {
+	kind := k.Kind
+	if k.Group != "" {
+		kind += "." + k.Group
+	}
+	if k.Version != "" {
+		kind += "/" + k.Version
+	}
+	return kind
+}
+
+// Retention defines how long a captured TrashedResource is kept. The values are summed.
+// +kubebuilder:validation:XValidation:rule="self.minutes + self.hours + self.days > 0",message="retention must be greater than zero"
+type Retention struct {
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	Minutes int64 `json:"minutes,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	Hours int64 `json:"hours,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	Days int64 `json:"days,omitempty"`
+}
+
+// TrashedResourcePolicySpec defines which objects are captured as TrashedResources.
+type TrashedResourcePolicySpec struct {
+	// Kinds to capture.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	Kinds []PolicyKind `json:"kinds"`
+
+	// Actions to capture.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Actions []TrashedAction `json:"actions"`
+
+	// NamespaceSelector restricts the capture to objects in namespaces matching the selector.
+	// Cluster scoped objects are only captured when it is not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ExcludedNamespaces are never captured by this policy.
+	// +optional
+	// +listType=set
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	// LabelSelector restricts the capture to objects with matching labels.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Retention of the captured TrashedResources. When not set, the retention of the
+	// controller ConfigMap is used.
+	// +optional
+	Retention *Retention `json:"retention,omitempty"`
+}
+
+// TrashedResourcePolicyStatus defines the observed state of TrashedResourcePolicy.
+type TrashedResourcePolicyStatus struct {
+	// ObservedGeneration is the last generation processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions of the policy. The Ready condition reports whether every kind could be resolved.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=trp,categories=mox-app-br
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// TrashedResourcePolicy is the Schema for the TrashedResourcePolicy API.
+type TrashedResourcePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrashedResourcePolicySpec   `json:"spec,omitempty"`
+	Status TrashedResourcePolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrashedResourcePolicyList contains a list of TrashedResourcePolicy.
+type TrashedResourcePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrashedResourcePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrashedResourcePolicy{}, &TrashedResourcePolicyList{})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"trashed-resources/api/v1alpha2"
+)
+
+// invalidKeepUntilAnnotation keeps a KeepUntil that is not an RFC3339 timestamp, stored before it was
+// validated, so it survives the round trip through v1alpha2 where KeepUntil is left empty.
+const invalidKeepUntilAnnotation = "mox.app.br/invalid-keep-until"
+
+// ConvertTo converts this TrashedResource to the Hub version (v1alpha2).
+func (src *TrashedResource) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha2.TrashedResource)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.Data = src.Spec.Data
+	dst.Spec.KeepUntil = metav1.Time{}
+	if keepUntil, err := time.Parse(time.RFC3339, src.Spec.KeepUntil); err == nil {
+		dst.Spec.KeepUntil = metav1.Time{Time: keepUntil}
+	} else if src.Spec.KeepUntil != "" {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[invalidKeepUntilAnnotation] = src.Spec.KeepUntil
+	}
+	dst.Spec.Original = v1alpha2.OriginalReference(src.Spec.Original)
+	dst.Spec.Action = v1alpha2.TrashedAction(src.Spec.Action)
+	dst.Spec.DataProtection = v1alpha2.DataProtection(src.Spec.DataProtection)
+	dst.Spec.Encrypted = nil
+	if src.Spec.Encrypted != nil {
+		dst.Spec.Encrypted = &v1alpha2.EncryptedData{
+			KeyRef: v1alpha2.SecretKeyReference(src.Spec.Encrypted.KeyRef),
+			Key:    src.Spec.Encrypted.Key,
+			Data:   src.Spec.Encrypted.Data,
+		}
+	}
+	dst.Spec.DataSecretRef = (*v1alpha2.SecretKeyReference)(src.Spec.DataSecretRef)
+	dst.Spec.Encoding = v1alpha2.DataEncoding(src.Spec.Encoding)
+	dst.Spec.External = (*v1alpha2.ExternalData)(src.Spec.External)
+	dst.Spec.Size = (*v1alpha2.DataSize)(src.Spec.Size)
+	dst.Spec.RequestedBy = src.Spec.RequestedBy
+
+	dst.Status.Phase = v1alpha2.TrashedResourcePhase(src.Status.Phase)
+	dst.Status.Original = v1alpha2.OriginalObject(src.Status.Original)
+	dst.Status.Action = v1alpha2.TrashedAction(src.Status.Action)
+	dst.Status.ExpiresAt = src.Status.ExpiresAt.DeepCopy()
+	dst.Status.Conditions = append([]metav1.Condition(nil), src.Status.Conditions...)
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha2) to this version.
+func (dst *TrashedResource) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha2.TrashedResource)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.Data = src.Spec.Data
+	dst.Spec.KeepUntil = ""
+	if !src.Spec.KeepUntil.IsZero() {
+		dst.Spec.KeepUntil = src.Spec.KeepUntil.UTC().Format(time.RFC3339)
+	} else if invalid, found := dst.Annotations[invalidKeepUntilAnnotation]; found {
+		dst.Spec.KeepUntil = invalid
+	}
+	delete(dst.Annotations, invalidKeepUntilAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	dst.Spec.Original = OriginalReference(src.Spec.Original)
+	dst.Spec.Action = TrashedAction(src.Spec.Action)
+	dst.Spec.DataProtection = DataProtection(src.Spec.DataProtection)
+	dst.Spec.Encrypted = nil
+	if src.Spec.Encrypted != nil {
+		dst.Spec.Encrypted = &EncryptedData{
+			KeyRef: SecretKeyReference(src.Spec.Encrypted.KeyRef),
+			Key:    src.Spec.Encrypted.Key,
+			Data:   src.Spec.Encrypted.Data,
+		}
+	}
+	dst.Spec.DataSecretRef = (*SecretKeyReference)(src.Spec.DataSecretRef)
+	dst.Spec.Encoding = DataEncoding(src.Spec.Encoding)
+	dst.Spec.External = (*ExternalData)(src.Spec.External)
+	dst.Spec.Size = (*DataSize)(src.Spec.Size)
+	dst.Spec.RequestedBy = src.Spec.RequestedBy
+
+	dst.Status.Phase = TrashedResourcePhase(src.Status.Phase)
+	dst.Status.Original = OriginalObject(src.Status.Original)
+	dst.Status.Action = TrashedAction(src.Status.Action)
+	dst.Status.ExpiresAt = src.Status.ExpiresAt.DeepCopy()
+	dst.Status.Conditions = append([]metav1.Condition(nil), src.Status.Conditions...)
+
+	return nil
+}
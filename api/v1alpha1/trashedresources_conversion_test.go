@@ -0,0 +1,91 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"trashed-resources/api/v1alpha2"
+)
+
+func TestTrashedResourceConversion_RoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	src := &TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "trashed-deleted-secret-my-secret-20260301-230159",
+			Namespace:   "default",
+			Annotations: map[string]string{"OriginalName": "my-secret"},
+		},
+		Spec: TrashedResourceSpec{
+			Data:           "apiVersion: v1\nkind: Secret\n",
+			KeepUntil:      "2026-03-01T23:11:59Z",
+			Original:       OriginalReference{APIVersion: "v1", Kind: "Secret", Name: "my-secret", Namespace: "default"},
+			Action:         ActionDelete,
+			DataProtection: DataEncrypted,
+			Encrypted: &EncryptedData{
+				KeyRef: SecretKeyReference{Namespace: "trashed-resources-system", Name: "key", Key: "key"},
+				Key:    "a2V5",
+				Data:   "ZGF0YQ==",
+			},
+			Encoding: DataEncodingGzip,
+			External: &ExternalData{URL: "s3://backups/trashed", Key: "default/trashed-deleted-secret-my-secret",
+				EncryptedKey: "default/trashed-deleted-secret-my-secret.encrypted"},
+			Size:        &DataSize{Original: 2048, Stored: 512},
+			RequestedBy: "jane@example.com",
+		},
+		Status: TrashedResourceStatus{Phase: PhaseActive},
+	}
+
+	hub := &v1alpha2.TrashedResource{}
+	g.Expect(src.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub.Spec.KeepUntil.Time.Equal(time.Date(2026, 3, 1, 23, 11, 59, 0, time.UTC))).To(BeTrue())
+	g.Expect(hub.Spec.Original.Kind).To(Equal("Secret"))
+	g.Expect(hub.Spec.Action).To(Equal(v1alpha2.ActionDelete))
+	g.Expect(hub.Spec.DataProtection).To(Equal(v1alpha2.DataEncrypted))
+	g.Expect(hub.Spec.Encrypted.KeyRef.Name).To(Equal("key"))
+	g.Expect(hub.Spec.External.URL).To(Equal("s3://backups/trashed"))
+	g.Expect(hub.Spec.RequestedBy).To(Equal("jane@example.com"))
+	g.Expect(hub.Status.Phase).To(Equal(v1alpha2.PhaseActive))
+
+	dst := &TrashedResource{}
+	g.Expect(dst.ConvertFrom(hub)).To(Succeed())
+	g.Expect(dst).To(Equal(src))
+}
+
+func TestTrashedResourceConversion_InvalidKeepUntil(t *testing.T) {
+	g := NewWithT(t)
+
+	src := &TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+		Spec:       TrashedResourceSpec{Data: "{}", KeepUntil: "tomorrow"},
+	}
+
+	hub := &v1alpha2.TrashedResource{}
+	g.Expect(src.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub.Spec.KeepUntil.IsZero()).To(BeTrue())
+	g.Expect(hub.Annotations).To(HaveKeyWithValue(invalidKeepUntilAnnotation, "tomorrow"))
+
+	dst := &TrashedResource{}
+	g.Expect(dst.ConvertFrom(hub)).To(Succeed())
+	g.Expect(dst.Spec.KeepUntil).To(Equal("tomorrow"))
+	g.Expect(dst.Annotations).To(BeNil())
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -31,19 +32,217 @@ type TrashedResourceSpec struct {
 	// Data is the YAML content of the deleted resource
 	// +kubebuilder:validation:Required
 	Data string `json:"data"`
+	// KeepUntil is the time the TrashedResource is deleted, as an RFC3339 timestamp.
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])T([01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9]([.][0-9]+)?(Z|[+-]([01][0-9]|2[0-3]):[0-5][0-9])$')",message="keepUntil must be an RFC3339 timestamp, eg. 2026-03-01T23:11:59Z"
 	KeepUntil string `json:"keepUntil"`
+
+	// Original references the captured object.
+	// +optional
+	Original OriginalReference `json:"original,omitempty"`
+
+	// Action that was captured.
+	// +optional
+	Action TrashedAction `json:"action,omitempty"`
+
+	// DataProtection is how the sensitive fields of the captured object are stored. When set, Data holds
+	// the object with the values of data, stringData and binaryData redacted.
+	// +optional
+	DataProtection DataProtection `json:"dataProtection,omitempty"`
+
+	// Encrypted holds the whole captured object when DataProtection is Encrypted.
+	// +optional
+	Encrypted *EncryptedData `json:"encrypted,omitempty"`
+
+	// DataSecretRef references the Secret, owned by the TrashedResource, that holds the whole captured
+	// object when DataProtection is Secret.
+	// +optional
+	DataSecretRef *SecretKeyReference `json:"dataSecretRef,omitempty"`
+
+	// Encoding of Data and of the copies of DataProtection, plain YAML when empty.
+	// +optional
+	Encoding DataEncoding `json:"encoding,omitempty"`
+
+	// External references the storage backend that holds Data when it is too large to be stored in the
+	// TrashedResource, Data is empty then.
+	// +optional
+	External *ExternalData `json:"external,omitempty"`
+
+	// Size of the captured object.
+	// +optional
+	Size *DataSize `json:"size,omitempty"`
+
+	// RequestedBy is the user that deleted or updated the object, known when it is captured by the
+	// admission webhook.
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// DataEncoding is how a captured object is encoded.
+// +kubebuilder:validation:Enum=gzip
+type DataEncoding string
+
+// DataEncodingGzip is the YAML compressed with gzip and base64 encoded.
+const DataEncodingGzip DataEncoding = "gzip"
+
+// ExternalData references a captured object kept in a storage backend.
+type ExternalData struct {
+	// URL of the storage backend, eg. file:///var/lib/trashed-resources or s3://bucket/prefix.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+	// Key of the object in the storage backend.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// EncryptedKey of the encrypted object in the storage backend, when the object is encrypted.
+	// +optional
+	EncryptedKey string `json:"encryptedKey,omitempty"`
+}
+
+// DataSize is the size in bytes of a captured object.
+type DataSize struct {
+	// Original is the size of the YAML of the object.
+	Original int64 `json:"original"`
+	// Stored is the size of Data once encoded, before it is moved to a storage backend.
+	Stored int64 `json:"stored"`
+}
+
+// DataProtection is how the sensitive fields of a captured object are stored.
+// +kubebuilder:validation:Enum=Redacted;Encrypted;Secret
+type DataProtection string
+
+const (
+	// DataRedacted drops the sensitive values, the object can not be restored.
+	DataRedacted DataProtection = "Redacted"
+	// DataEncrypted encrypts the whole object with a key from a Secret.
+	DataEncrypted DataProtection = "Encrypted"
+	// DataSecret stores the whole object in a Secret owned by the TrashedResource.
+	DataSecret DataProtection = "Secret"
+)
+
+// SecretKeyReference references a key of a Secret.
+type SecretKeyReference struct {
+	// Namespace of the Secret, the namespace of the TrashedResource when empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+// EncryptedData is an AES-GCM envelope: the object is encrypted with a random data key, which is
+// encrypted with the key referenced by KeyRef.
+type EncryptedData struct {
+	// KeyRef references the key that encrypts the data key, 32 bytes long (AES-256).
+	// +kubebuilder:validation:Required
+	KeyRef SecretKeyReference `json:"keyRef"`
+	// Key is the encrypted data key, base64 encoded with the nonce first.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// Data is the encrypted object, base64 encoded with the nonce first. It is empty when the encrypted
+	// object is kept in the storage backend, see ExternalData.EncryptedKey.
+	// +optional
+	Data string `json:"data,omitempty"`
+}
+
+// OriginalReference references the object captured in a TrashedResource.
+type OriginalReference struct {
+	// APIVersion of the object (eg. apps/v1).
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Namespace of the object, empty for cluster scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// ActionLabel is set on every TrashedResource with the captured action (delete or update).
+const ActionLabel = "mox.app.br/action"
+
+// TrashedResourcePhase is the lifecycle phase of a TrashedResource.
+// +kubebuilder:validation:Enum=Active;Expiring;Restored;RestoreFailed
+type TrashedResourcePhase string
+
+const (
+	// PhaseActive is a TrashedResource kept until its expiry.
+	PhaseActive TrashedResourcePhase = "Active"
+	// PhaseExpiring is a TrashedResource with less than ExpiringThreshold of its retention left.
+	PhaseExpiring TrashedResourcePhase = "Expiring"
+	// PhaseRestored is a TrashedResource whose object was restored.
+	PhaseRestored TrashedResourcePhase = "Restored"
+	// PhaseRestoreFailed is a TrashedResource whose last restore failed.
+	PhaseRestoreFailed TrashedResourcePhase = "RestoreFailed"
+)
+
+// ExpiringThreshold is the fraction of the retention left under which a TrashedResource is Expiring.
+const ExpiringThreshold = 0.1
+
+// Condition types of a TrashedResource.
+const (
+	// ConditionCaptured reports whether Spec.Data holds a valid object.
+	ConditionCaptured = "Captured"
+	// ConditionExpiring reports whether the TrashedResource will be deleted soon.
+	ConditionExpiring = "Expiring"
+	// ConditionRestored reports the result of the last restore.
+	ConditionRestored = "Restored"
+)
+
+// OriginalObject identifies the object captured in a TrashedResource.
+type OriginalObject struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
 }
 
 // TrashedResourceStatus defines the observed state of TrashedResource.
 type TrashedResourceStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Phase of the TrashedResource.
+	// +optional
+	Phase TrashedResourcePhase `json:"phase,omitempty"`
+
+	// Original identifies the captured object.
+	// +optional
+	Original OriginalObject `json:"original,omitempty"`
+
+	// Action that was captured.
+	// +optional
+	Action TrashedAction `json:"action,omitempty"`
+
+	// ExpiresAt is the time the TrashedResource will be deleted, computed from Spec.KeepUntil.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Conditions of the TrashedResource: Captured, Expiring and Restored.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:deprecatedversion:warning="mox.app.br/v1alpha1 TrashedResource is deprecated, use mox.app.br/v1alpha2"
 // +kubebuilder:resource:shortName=tr,categories=mox-app-br
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.original.kind`
+// +kubebuilder:printcolumn:name="Original Name",type=string,JSONPath=`.spec.original.name`
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="KeepUntil",type=string,JSONPath=`.spec.keepUntil`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,priority=1
 
 // TrashedResource is the Schema for the TrashedResource API.
 type TrashedResource struct {
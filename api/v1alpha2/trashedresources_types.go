@@ -0,0 +1,302 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TrashedResourceSpec defines the desired state of TrashedResource.
+type TrashedResourceSpec struct {
+	// Data is the YAML content of the deleted resource
+	// +kubebuilder:validation:Required
+	Data string `json:"data"`
+
+	// KeepUntil is the time the TrashedResource is deleted, as an RFC3339 timestamp.
+	// +kubebuilder:validation:Required
+	KeepUntil metav1.Time `json:"keepUntil"`
+
+	// Original references the captured object.
+	// +optional
+	Original OriginalReference `json:"original,omitempty"`
+
+	// Action that was captured.
+	// +optional
+	Action TrashedAction `json:"action,omitempty"`
+
+	// DataProtection is how the sensitive fields of the captured object are stored. When set, Data holds
+	// the object with the values of data, stringData and binaryData redacted.
+	// +optional
+	DataProtection DataProtection `json:"dataProtection,omitempty"`
+
+	// Encrypted holds the whole captured object when DataProtection is Encrypted.
+	// +optional
+	Encrypted *EncryptedData `json:"encrypted,omitempty"`
+
+	// DataSecretRef references the Secret, owned by the TrashedResource, that holds the whole captured
+	// object when DataProtection is Secret.
+	// +optional
+	DataSecretRef *SecretKeyReference `json:"dataSecretRef,omitempty"`
+
+	// Encoding of Data and of the copies of DataProtection, plain YAML when empty.
+	// +optional
+	Encoding DataEncoding `json:"encoding,omitempty"`
+
+	// External references the storage backend that holds Data when it is too large to be stored in the
+	// TrashedResource, Data is empty then.
+	// +optional
+	External *ExternalData `json:"external,omitempty"`
+
+	// Size of the captured object.
+	// +optional
+	Size *DataSize `json:"size,omitempty"`
+
+	// RequestedBy is the user that deleted or updated the object, known when it is captured by the
+	// admission webhook.
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// DataEncoding is how a captured object is encoded.
+// +kubebuilder:validation:Enum=gzip
+type DataEncoding string
+
+// DataEncodingGzip is the YAML compressed with gzip and base64 encoded.
+const DataEncodingGzip DataEncoding = "gzip"
+
+// ExternalData references a captured object kept in a storage backend.
+type ExternalData struct {
+	// URL of the storage backend, eg. file:///var/lib/trashed-resources or s3://bucket/prefix.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+	// Key of the object in the storage backend.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// EncryptedKey of the encrypted object in the storage backend, when the object is encrypted.
+	// +optional
+	EncryptedKey string `json:"encryptedKey,omitempty"`
+}
+
+// DataSize is the size in bytes of a captured object.
+type DataSize struct {
+	// Original is the size of the YAML of the object.
+	Original int64 `json:"original"`
+	// Stored is the size of Data once encoded, before it is moved to a storage backend.
+	Stored int64 `json:"stored"`
+}
+
+// DataProtection is how the sensitive fields of a captured object are stored.
+// +kubebuilder:validation:Enum=Redacted;Encrypted;Secret
+type DataProtection string
+
+const (
+	// DataRedacted drops the sensitive values, the object can not be restored.
+	DataRedacted DataProtection = "Redacted"
+	// DataEncrypted encrypts the whole object with a key from a Secret.
+	DataEncrypted DataProtection = "Encrypted"
+	// DataSecret stores the whole object in a Secret owned by the TrashedResource.
+	DataSecret DataProtection = "Secret"
+)
+
+// SecretKeyReference references a key of a Secret.
+type SecretKeyReference struct {
+	// Namespace of the Secret, the namespace of the TrashedResource when empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+// EncryptedData is an AES-GCM envelope: the object is encrypted with a random data key, which is
+// encrypted with the key referenced by KeyRef.
+type EncryptedData struct {
+	// KeyRef references the key that encrypts the data key, 32 bytes long (AES-256).
+	// +kubebuilder:validation:Required
+	KeyRef SecretKeyReference `json:"keyRef"`
+	// Key is the encrypted data key, base64 encoded with the nonce first.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// Data is the encrypted object, base64 encoded with the nonce first. It is empty when the encrypted
+	// object is kept in the storage backend, see ExternalData.EncryptedKey.
+	// +optional
+	Data string `json:"data,omitempty"`
+}
+
+// TrashedAction is an action over an object that can be captured.
+// +kubebuilder:validation:Enum=delete;update
+type TrashedAction string
+
+const (
+	// ActionDelete captures the object when it is deleted.
+	ActionDelete TrashedAction = "delete"
+	// ActionUpdate captures the previous state of the object when it is updated.
+	ActionUpdate TrashedAction = "update"
+)
+
+// ActionLabel is set on every TrashedResource with the captured action (delete or update).
+const ActionLabel = "mox.app.br/action"
+
+// OriginalHashLabel is set on every TrashedResource with a hash of the kind, namespace and name of the
+// original object, so all the revisions captured from the same object can be selected together.
+const OriginalHashLabel = "mox.app.br/original-hash"
+
+// OriginalNamespaceLabel is set on the TrashedResources of namespaced objects with the namespace of the
+// original object, so the ones kept in a vault namespace can be selected by it.
+const OriginalNamespaceLabel = "mox.app.br/original-namespace"
+
+// CaptureReasonLabel is set on the TrashedResources not captured from a delete or update of the object, with
+// the reason of the capture.
+const CaptureReasonLabel = "mox.app.br/capture-reason"
+
+// CaptureReasonDetectedOffline is the reason of the objects found deleted when the controller starts, they are
+// captured from their last known state.
+const CaptureReasonDetectedOffline = "detected-offline"
+
+// DataSecretLabel is set, with the name of the TrashedResource, on the Secret that holds its captured object.
+// These Secrets are never captured.
+const DataSecretLabel = "mox.app.br/trashed-resource"
+
+// ExternalDataFinalizer is set on the TrashedResources with Data in a storage backend, it is removed once
+// the data is deleted from the backend.
+const ExternalDataFinalizer = "mox.app.br/external-data"
+
+// TrashProtectionFinalizer is set on the objects of the finalizerKinds, so they are only deleted once captured.
+const TrashProtectionFinalizer = "mox.app.br/trash-protection"
+
+// CapturedAnnotation is set, with the capture mode, on the objects captured before their deletion completes.
+const CapturedAnnotation = "mox.app.br/captured"
+
+// OriginalReference references the object captured in a TrashedResource.
+type OriginalReference struct {
+	// APIVersion of the object (eg. apps/v1).
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Namespace of the object, empty for cluster scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// TrashedResourcePhase is the lifecycle phase of a TrashedResource.
+// +kubebuilder:validation:Enum=Active;Expiring;Restored;RestoreFailed
+type TrashedResourcePhase string
+
+const (
+	// PhaseActive is a TrashedResource kept until its expiry.
+	PhaseActive TrashedResourcePhase = "Active"
+	// PhaseExpiring is a TrashedResource with less than ExpiringThreshold of its retention left.
+	PhaseExpiring TrashedResourcePhase = "Expiring"
+	// PhaseRestored is a TrashedResource whose object was restored.
+	PhaseRestored TrashedResourcePhase = "Restored"
+	// PhaseRestoreFailed is a TrashedResource whose last restore failed.
+	PhaseRestoreFailed TrashedResourcePhase = "RestoreFailed"
+)
+
+// ExpiringThreshold is the fraction of the retention left under which a TrashedResource is Expiring.
+const ExpiringThreshold = 0.1
+
+// Condition types of a TrashedResource.
+const (
+	// ConditionCaptured reports whether Spec.Data holds a valid object.
+	ConditionCaptured = "Captured"
+	// ConditionExpiring reports whether the TrashedResource will be deleted soon.
+	ConditionExpiring = "Expiring"
+	// ConditionRestored reports the result of the last restore.
+	ConditionRestored = "Restored"
+)
+
+// OriginalObject identifies the object captured in a TrashedResource.
+type OriginalObject struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// TrashedResourceStatus defines the observed state of TrashedResource.
+type TrashedResourceStatus struct {
+	// Phase of the TrashedResource.
+	// +optional
+	Phase TrashedResourcePhase `json:"phase,omitempty"`
+
+	// Original identifies the captured object.
+	// +optional
+	Original OriginalObject `json:"original,omitempty"`
+
+	// Action that was captured.
+	// +optional
+	Action TrashedAction `json:"action,omitempty"`
+
+	// ExpiresAt is the time the TrashedResource will be deleted, computed from Spec.KeepUntil.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Conditions of the TrashedResource: Captured, Expiring and Restored.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=tr,categories=mox-app-br
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.original.kind`
+// +kubebuilder:printcolumn:name="Original Name",type=string,JSONPath=`.spec.original.name`
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="KeepUntil",type=date,JSONPath=`.spec.keepUntil`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,priority=1
+
+// TrashedResource is the Schema for the TrashedResource API.
+type TrashedResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrashedResourceSpec   `json:"spec,omitempty"`
+	Status TrashedResourceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrashedResourceList contains a list of TrashedResource.
+type TrashedResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrashedResource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrashedResource{}, &TrashedResourceList{})
+}
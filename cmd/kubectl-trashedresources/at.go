@@ -0,0 +1,214 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+)
+
+// atOptions reconstructs a namespace at a point in time.
+type atOptions struct {
+	at time.Time
+	// apply restores the reconstructed objects instead of printing them.
+	apply bool
+	out   io.Writer
+	// errOut receives the warnings, so they are not mixed with the printed objects.
+	errOut io.Writer
+}
+
+func (o atOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+func (o atOptions) errWriter() io.Writer {
+	if o.errOut == nil {
+		return os.Stderr
+	}
+	return o.errOut
+}
+
+// objectState is the state of a captured object at a point in time. The manifest comes from
+// trashed, or from the live object when trashed is nil.
+type objectState struct {
+	original moxv1alpha2.OriginalObject
+	trashed  *moxv1alpha2.TrashedResource
+}
+
+func atCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := atOptions{}
+	var at string
+
+	atCmd := &cobra.Command{
+		Use:   "at",
+		Short: "Reconstructs the captured objects of a namespace as they were at a point in time",
+		Long: `Example: kubectl trashedresources at --namespace team-a --time 2026-03-01T23:00:00Z > team-a.yaml
+or kubectl trashedresources at --namespace team-a --time 2h --apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out, opts.errOut = cmd.OutOrStdout(), cmd.ErrOrStderr()
+			if at == "" {
+				return fmt.Errorf("the --time flag is required")
+			}
+			var err error
+			if opts.at, err = parseTimeFlag("time", at); err != nil {
+				return err
+			}
+			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+			k8sClient, err := clientGetter(kubernetesConfigFlags)
+			if err != nil {
+				return err
+			}
+			return snapshotAt(k8sClient, ns, opts)
+		},
+	}
+
+	atCmd.Flags().StringVar(&at, "time", "", "Point in time (RFC3339, or a duration before now like 2h)")
+	atCmd.Flags().BoolVar(&opts.apply, "apply", false, "Restore the reconstructed objects instead of printing them")
+
+	return atCmd
+}
+
+// snapshotAt prints, as a multi-document YAML, every object captured from the namespace as it was at
+// opts.at or, with opts.apply, restores them. The objects are printed whole, as they are restored, and
+// the redacted ones are skipped with a warning.
+func snapshotAt(c client.Client, namespace string, opts atOptions) error {
+	ctx := context.Background()
+	out := opts.writer()
+
+	// The objects of a namespace can be captured in other namespaces, so they are searched in all of them.
+	list, err := trashedresources.NewTrashedResourceInteractor(c).List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list TrashedResources: %v", err)
+	}
+	states := objectStatesAt(list.Items, namespace, opts.at)
+
+	if opts.apply {
+		results := []bulkRestoreResult{}
+		for _, state := range states {
+			// The live object is already in the state it had at that time.
+			if state.trashed == nil {
+				continue
+			}
+			object, err := decodeRestorableObject(ctx, c, state.trashed)
+			if err == nil {
+				err = rollbackLiveObject(ctx, c, object, restoreOptions{})
+			}
+			results = append(results, bulkRestoreResult{trashed: *state.trashed, original: state.original, err: err})
+		}
+		if len(results) == 0 {
+			_, _ = fmt.Fprintln(out, "Nothing to restore")
+			return nil
+		}
+		return printBulkRestoreReport(results, dryRunNone, out)
+	}
+
+	for _, state := range states {
+		var object *unstructured.Unstructured
+		var source string
+		if state.trashed != nil {
+			if state.trashed.Spec.DataProtection == moxv1alpha2.DataRedacted {
+				_, _ = fmt.Fprintf(opts.errWriter(), "Warning: skipping %s %s, the sensitive data of TrashedResource "+
+					"%s/%s was redacted\n", state.original.Kind, state.original.Name, state.trashed.Namespace,
+					state.trashed.Name)
+				continue
+			}
+			object, err = decodeRestorableObject(ctx, c, state.trashed)
+			source = "trashedresource " + state.trashed.Namespace + "/" + state.trashed.Name
+		} else {
+			object, err = getLiveObject(ctx, c, objectFromOriginal(state.original))
+			source = "live object"
+		}
+		if err != nil {
+			return err
+		}
+		// The live object was deleted after its last capture, its state at that time is unknown.
+		if object == nil {
+			continue
+		}
+
+		manifest, err := goyaml.Marshal(object.Object)
+		if err != nil {
+			return fmt.Errorf("failed to print the manifest: %v", err)
+		}
+		_, err = fmt.Fprintf(out, "---\n# source: %s\n%s", source, manifest)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectStatesAt returns the state at the time at of every object captured from the namespace, in restore order.
+// A capture holds the state of the object before the change, so the state at a time is the first capture after
+// it. When there is none, the object was deleted before that time when its last capture is a deletion, otherwise
+// it was not changed since then and the live object is used.
+func objectStatesAt(items []moxv1alpha2.TrashedResource, namespace string, at time.Time) []objectState {
+	captures := map[string][]moxv1alpha2.TrashedResource{}
+	originals := map[string]moxv1alpha2.OriginalObject{}
+	for _, trashed := range items {
+		original, err := trashedresources.OriginalOf(&trashed)
+		if err != nil {
+			continue
+		}
+		if original.Namespace != namespace && (original.Kind != "Namespace" || original.Name != namespace) {
+			continue
+		}
+		key := original.Group + "/" + original.Kind + "/" + original.Namespace + "/" + original.Name
+		captures[key] = append(captures[key], trashed)
+		originals[key] = original
+	}
+
+	states := []objectState{}
+	for key, objectCaptures := range captures {
+		slices.SortFunc(objectCaptures, func(a, b moxv1alpha2.TrashedResource) int {
+			return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+		})
+		after := slices.IndexFunc(objectCaptures, func(trashed moxv1alpha2.TrashedResource) bool {
+			return trashed.CreationTimestamp.After(at)
+		})
+		switch {
+		case after >= 0:
+			states = append(states, objectState{original: originals[key], trashed: &objectCaptures[after]})
+		case trashedresources.ActionOf(&objectCaptures[len(objectCaptures)-1]) != moxv1alpha2.ActionDelete:
+			states = append(states, objectState{original: originals[key]})
+		}
+	}
+
+	slices.SortFunc(states, func(a, b objectState) int {
+		return cmp.Or(
+			cmp.Compare(restoreRank(a.original.Kind), restoreRank(b.original.Kind)),
+			strings.Compare(a.original.Kind, b.original.Kind),
+			strings.Compare(a.original.Name, b.original.Name),
+		)
+	})
+	return states
+}
+
+func objectFromOriginal(original moxv1alpha2.OriginalObject) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(schema.GroupVersionKind{Group: original.Group, Version: original.Version, Kind: original.Kind})
+	object.SetName(original.Name)
+	object.SetNamespace(original.Namespace)
+	return object
+}
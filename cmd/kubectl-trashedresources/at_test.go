@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+var _ = Describe("kubectl-trashedresources at", func() {
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+	now := time.Now()
+
+	configMap := func(name, namespace, value string) string {
+		return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: " + namespace +
+			"\n  resourceVersion: \"7\"\ndata:\n  key: " + value + "\n"
+	}
+
+	BeforeEach(func() {
+		k8sClient = newTestClientBuilder().Build()
+		out = &bytes.Buffer{}
+
+		for _, trashed := range []*moxv1alpha2.TrashedResource{
+			// Updated before and after the point in time: the capture after it holds its state
+			newTrashedFixture("trashed-updated-configmap-cfg-1", "team-a", moxv1alpha2.ActionUpdate, 3*time.Hour,
+				configMap("cfg", "team-a", "v1")),
+			newTrashedFixture("trashed-updated-configmap-cfg-2", "team-a", moxv1alpha2.ActionUpdate, time.Hour,
+				configMap("cfg", "team-a", "v2")),
+			// Deleted before the point in time
+			newTrashedFixture("trashed-deleted-configmap-gone", "team-a", moxv1alpha2.ActionDelete, 3*time.Hour,
+				configMap("gone", "team-a", "old")),
+			// Only updated before the point in time: the live object holds its state
+			newTrashedFixture("trashed-updated-configmap-stable", "team-a", moxv1alpha2.ActionUpdate, 5*time.Hour,
+				configMap("stable", "team-a", "older")),
+			// Deleted after the point in time
+			newTrashedFixture("trashed-deleted-serviceaccount-web", "team-a", moxv1alpha2.ActionDelete, 30*time.Minute,
+				"apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: web\n  namespace: team-a\n"),
+			newTrashedFixture("trashed-deleted-configmap-other", "other", moxv1alpha2.ActionDelete, 30*time.Minute,
+				configMap("other", "other", "other")),
+		} {
+			Expect(k8sClient.Create(ctx, trashed)).To(Succeed())
+		}
+		Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "team-a"},
+			Data:       map[string]string{"key": "live"},
+		})).To(Succeed())
+	})
+
+	It("should print the state of every captured object of the namespace at the time", func() {
+		Expect(snapshotAt(k8sClient, "team-a", atOptions{at: now.Add(-2 * time.Hour), out: out})).To(Succeed())
+
+		documents := strings.Split(out.String(), "---\n")[1:]
+		Expect(documents).To(HaveLen(3))
+		Expect(documents[0]).To(HavePrefix("# source: trashedresource team-a/trashed-deleted-serviceaccount-web\n"))
+		Expect(documents[1]).To(HavePrefix("# source: trashedresource team-a/trashed-updated-configmap-cfg-2\n"))
+		Expect(documents[2]).To(HavePrefix("# source: live object\n"))
+
+		cfg := &corev1.ConfigMap{}
+		Expect(sigsyaml.Unmarshal([]byte(documents[1]), cfg)).To(Succeed())
+		Expect(cfg.Data["key"]).To(Equal("v2"))
+		Expect(cfg.ResourceVersion).To(BeEmpty())
+		stable := &corev1.ConfigMap{}
+		Expect(sigsyaml.Unmarshal([]byte(documents[2]), stable)).To(Succeed())
+		Expect(stable.Data["key"]).To(Equal("live"))
+		Expect(out.String()).NotTo(ContainSubstring("name: gone"))
+		Expect(out.String()).NotTo(ContainSubstring("name: other"))
+	})
+
+	It("should include the objects deleted after an earlier time", func() {
+		Expect(snapshotAt(k8sClient, "team-a", atOptions{at: now.Add(-4 * time.Hour), out: out})).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("name: gone"))
+		Expect(out.String()).To(ContainSubstring("key: v1"))
+		Expect(out.String()).To(ContainSubstring("key: live"))
+
+		out.Reset()
+		Expect(snapshotAt(k8sClient, "team-a", atOptions{at: now.Add(-6 * time.Hour), out: out})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("key: older"))
+	})
+
+	It("should print the protected objects whole and skip the redacted ones", func() {
+		protected := newTrashedFixture("trashed-deleted-secret-db", "team-a", moxv1alpha2.ActionDelete, 30*time.Minute,
+			"apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\n  namespace: team-a\ndata:\n  password: REDACTED\n")
+		protected.Spec.DataProtection = moxv1alpha2.DataSecret
+		protected.Spec.DataSecretRef = &moxv1alpha2.SecretKeyReference{Name: "trashed-deleted-secret-db", Key: "manifest"}
+		redacted := newTrashedFixture("trashed-deleted-secret-api", "team-a", moxv1alpha2.ActionDelete, 30*time.Minute,
+			"apiVersion: v1\nkind: Secret\nmetadata:\n  name: api\n  namespace: team-a\ndata:\n  token: REDACTED\n")
+		redacted.Spec.DataProtection = moxv1alpha2.DataRedacted
+		Expect(k8sClient.Create(ctx, protected)).To(Succeed())
+		Expect(k8sClient.Create(ctx, redacted)).To(Succeed())
+		Expect(k8sClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-secret-db", Namespace: "team-a"},
+			Data: map[string][]byte{"manifest": []byte(
+				"apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\n  namespace: team-a\ndata:\n  password: czNjcjN0\n")},
+		})).To(Succeed())
+
+		errOut := &bytes.Buffer{}
+		Expect(snapshotAt(k8sClient, "team-a", atOptions{at: now.Add(-time.Hour), out: out, errOut: errOut})).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("password: czNjcjN0"))
+		Expect(out.String()).NotTo(ContainSubstring("REDACTED"))
+		Expect(out.String()).NotTo(ContainSubstring("name: api"))
+		Expect(errOut.String()).To(Equal("Warning: skipping Secret api, the sensitive data of TrashedResource " +
+			"team-a/trashed-deleted-secret-api was redacted\n"))
+	})
+
+	It("should apply the reconstructed objects", func() {
+		Expect(snapshotAt(k8sClient, "team-a", atOptions{at: now.Add(-2 * time.Hour), apply: true, out: out})).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "web", Namespace: "team-a"}, &corev1.ServiceAccount{})).To(Succeed())
+		cfg := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cfg", Namespace: "team-a"}, cfg)).To(Succeed())
+		Expect(cfg.Data["key"]).To(Equal("v2"))
+		stable := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "stable", Namespace: "team-a"}, stable)).To(Succeed())
+		Expect(stable.Data["key"]).To(Equal("live"))
+		Expect(out.String()).To(ContainSubstring("Total: 2, succeeded: 2, failed: 0"))
+	})
+
+	It("should require the time via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "team-a"
+		configFlags.Namespace = &namespace
+
+		atCmd := atCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		atCmd.SetArgs([]string{})
+		atCmd.SetOut(GinkgoWriter)
+		atCmd.SetErr(GinkgoWriter)
+
+		err := atCmd.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("--time"))
+	})
+})
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+)
+
+// restoreKindOrder is the order in which the kinds are restored by a bulk restore, so the objects
+// are created after the ones they depend on. Other kinds are restored last.
+var restoreKindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"PriorityClass",
+	"StorageClass",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"LimitRange",
+	"ResourceQuota",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"Role",
+	"ClusterRoleBinding",
+	"RoleBinding",
+	"Service",
+	"Deployment",
+	"StatefulSet",
+	"DaemonSet",
+	"ReplicaSet",
+	"Job",
+	"CronJob",
+	"Pod",
+	"Ingress",
+	"HorizontalPodAutoscaler",
+	"PodDisruptionBudget",
+}
+
+// bulkRestoreOptions selects the TrashedResources restored together. Zero values do not filter.
+type bulkRestoreOptions struct {
+	// allFromNamespace restores every object captured in this namespace, and the namespace itself.
+	allFromNamespace string
+	// deletedAfter and deletedBefore filter by the creation of the TrashedResource.
+	deletedAfter  time.Time
+	deletedBefore time.Time
+	// selector is a label selector of the TrashedResources.
+	selector string
+	// originalName is the name of the original object.
+	originalName  string
+	allNamespaces bool
+	// includeUpdates restores the captures of updates too, by default only the deleted objects are restored.
+	includeUpdates bool
+}
+
+func (o bulkRestoreOptions) enabled() bool {
+	return o.allFromNamespace != "" || !o.deletedAfter.IsZero() || !o.deletedBefore.IsZero() ||
+		o.selector != "" || o.originalName != ""
+}
+
+// bulkRestoreResult is the result of the restore of one TrashedResource.
+type bulkRestoreResult struct {
+	trashed  moxv1alpha2.TrashedResource
+	original moxv1alpha2.OriginalObject
+	err      error
+}
+
+// bulkRestore restores every selected TrashedResource in dependency order, continuing on errors,
+// and prints the result of each one. Returns an error when any restore failed.
+func bulkRestore(c client.Client, namespace string, opts bulkRestoreOptions, restoreOpts restoreOptions) error {
+	ctx := context.Background()
+	out := restoreOpts.writer()
+
+	listOpts := []client.ListOption{}
+	// The objects of a namespace can be captured in other namespaces, so they are searched in all of them.
+	if namespace != "" && !opts.allNamespaces && opts.allFromNamespace == "" {
+		listOpts = trashListOptions(namespace)
+	}
+	if opts.selector != "" {
+		selector, err := labels.Parse(opts.selector)
+		if err != nil {
+			return fmt.Errorf("invalid --selector value: %v", err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	list := &moxv1alpha2.TrashedResourceList{}
+	if err := c.List(ctx, list, listOpts...); err != nil {
+		return fmt.Errorf("failed to list TrashedResources: %v", err)
+	}
+
+	selected := selectForBulkRestore(list.Items, opts)
+	if len(selected) == 0 {
+		_, _ = fmt.Fprintln(out, "No TrashedResources to restore")
+		return nil
+	}
+
+	// The progress of each restore is replaced by the report.
+	restoreOpts.out = io.Discard
+	results := make([]bulkRestoreResult, 0, len(selected))
+	for _, trashed := range selected {
+		original, _ := trashedresources.OriginalOf(&trashed)
+		err := restoreResource(c, trashed.Name, trashed.Namespace, restoreOpts)
+		results = append(results, bulkRestoreResult{trashed: trashed, original: original, err: err})
+	}
+
+	return printBulkRestoreReport(results, restoreOpts.dryRun, out)
+}
+
+// selectForBulkRestore returns the TrashedResources that match the options, only the latest one of
+// each original object, sorted in the order they must be restored. The captures of updates, whose
+// objects are usually still in the cluster, are only selected with includeUpdates.
+func selectForBulkRestore(items []moxv1alpha2.TrashedResource, opts bulkRestoreOptions) []moxv1alpha2.TrashedResource {
+	latest := map[string]moxv1alpha2.TrashedResource{}
+	originals := map[string]moxv1alpha2.OriginalObject{}
+	for _, trashed := range items {
+		original, _ := trashedresources.OriginalOf(&trashed)
+		created := trashed.CreationTimestamp.Time
+		switch {
+		case !opts.includeUpdates && trashedresources.ActionOf(&trashed) != moxv1alpha2.ActionDelete:
+			continue
+		case opts.allFromNamespace != "" && original.Namespace != opts.allFromNamespace &&
+			(original.Kind != "Namespace" || original.Name != opts.allFromNamespace):
+			continue
+		case opts.originalName != "" && original.Name != opts.originalName:
+			continue
+		case !opts.deletedAfter.IsZero() && created.Before(opts.deletedAfter):
+			continue
+		case !opts.deletedBefore.IsZero() && created.After(opts.deletedBefore):
+			continue
+		}
+
+		key := original.Group + "/" + original.Kind + "/" + original.Namespace + "/" + original.Name
+		if current, found := latest[key]; found && !current.CreationTimestamp.Before(&trashed.CreationTimestamp) {
+			continue
+		}
+		latest[key] = trashed
+		originals[key] = original
+	}
+
+	keys := make([]string, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(restoreRank(originals[a].Kind), restoreRank(originals[b].Kind)),
+			strings.Compare(originals[a].Kind, originals[b].Kind),
+			strings.Compare(originals[a].Namespace, originals[b].Namespace),
+			strings.Compare(originals[a].Name, originals[b].Name),
+		)
+	})
+
+	selected := make([]moxv1alpha2.TrashedResource, 0, len(keys))
+	for _, key := range keys {
+		selected = append(selected, latest[key])
+	}
+	return selected
+}
+
+func restoreRank(kind string) int {
+	if rank := slices.Index(restoreKindOrder, kind); rank >= 0 {
+		return rank
+	}
+	return len(restoreKindOrder)
+}
+
+func printBulkRestoreReport(results []bulkRestoreResult, dryRun string, out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tTRASHEDRESOURCE\tRESULT")
+	failed := 0
+	for _, result := range results {
+		status := "restored"
+		if dryRun == dryRunClient || dryRun == dryRunServer {
+			status = "would be restored (dry run)"
+		}
+		if result.err != nil {
+			failed++
+			status = "failed: " + result.err.Error()
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\n",
+			valueOrNone(result.original.Kind),
+			valueOrNone(result.original.Namespace),
+			valueOrNone(result.original.Name),
+			result.trashed.Namespace,
+			result.trashed.Name,
+			status,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "Total: %d, succeeded: %d, failed: %d\n", len(results), len(results)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d restores failed", failed, len(results))
+	}
+	return nil
+}
+
+// parseTimeFlag parses a time flag as RFC3339 or as a duration before now (e.g. 2h, 1d).
+func parseTimeFlag(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	ago, err := parseDurationFlag(flag, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s value %q, use a RFC3339 time or a duration like 2h", flag, value)
+	}
+	return time.Now().Add(-ago), nil
+}
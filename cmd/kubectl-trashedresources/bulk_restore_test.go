@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("kubectl-trashedresources bulk restore", func() {
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+	now := time.Now()
+
+	exists := func(object client.Object, name, namespace string) bool {
+		return k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, object) == nil
+	}
+
+	BeforeEach(func() {
+		k8sClient = newTestClientBuilder().Build()
+		out = &bytes.Buffer{}
+
+		for _, trashed := range []*moxv1alpha2.TrashedResource{
+			newTrashedFixture("trashed-deleted-deployment-web", "team-a", moxv1alpha2.ActionDelete, 10*time.Minute, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: team-a
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`),
+			newTrashedFixture("trashed-deleted-namespace-team-a", "default", moxv1alpha2.ActionDelete, 10*time.Minute, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+`),
+			newTrashedFixture("trashed-updated-configmap-web-config", "team-a", moxv1alpha2.ActionUpdate, 3*time.Hour, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: team-a
+data:
+  key: older
+`),
+			newTrashedFixture("trashed-deleted-configmap-web-config", "team-a", moxv1alpha2.ActionDelete, 10*time.Minute, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: team-a
+data:
+  key: latest
+`),
+			newTrashedFixture("trashed-deleted-serviceaccount-web", "team-a", moxv1alpha2.ActionDelete, 10*time.Minute, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: web
+  namespace: team-a
+`),
+			newTrashedFixture("trashed-deleted-configmap-other", "other", moxv1alpha2.ActionDelete, 10*time.Minute, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: other
+`),
+		} {
+			Expect(k8sClient.Create(ctx, trashed)).To(Succeed())
+		}
+	})
+
+	It("should select the latest capture of each object in dependency order", func() {
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(k8sClient.List(ctx, list)).To(Succeed())
+
+		selected := selectForBulkRestore(list.Items, bulkRestoreOptions{allFromNamespace: "team-a"})
+		names := []string{}
+		for _, trashed := range selected {
+			names = append(names, trashed.Name)
+		}
+		Expect(names).To(Equal([]string{
+			"trashed-deleted-namespace-team-a",
+			"trashed-deleted-serviceaccount-web",
+			"trashed-deleted-configmap-web-config",
+			"trashed-deleted-deployment-web",
+		}))
+	})
+
+	It("should only select the updates of objects still in the cluster with includeUpdates", func() {
+		Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "team-a"},
+			Data:       map[string]string{"key": "current"},
+		})).To(Succeed())
+		Expect(k8sClient.Create(ctx, newTrashedFixture("trashed-updated-configmap-live", "team-a", moxv1alpha2.ActionUpdate,
+			time.Minute, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: live
+  namespace: team-a
+data:
+  key: previous
+`))).To(Succeed())
+
+		Expect(bulkRestore(k8sClient, "team-a", bulkRestoreOptions{originalName: "live"},
+			restoreOptions{out: out})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("No TrashedResources to restore"))
+
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(k8sClient.List(ctx, list)).To(Succeed())
+		selected := selectForBulkRestore(list.Items, bulkRestoreOptions{originalName: "live", includeUpdates: true})
+		Expect(selected).To(HaveLen(1))
+		Expect(selected[0].Name).To(Equal("trashed-updated-configmap-live"))
+	})
+
+	It("should restore every object of a namespace and report each one", func() {
+		Expect(bulkRestore(k8sClient, "default", bulkRestoreOptions{allFromNamespace: "team-a"},
+			restoreOptions{out: out})).To(Succeed())
+
+		Expect(exists(&corev1.Namespace{}, "team-a", "")).To(BeTrue())
+		Expect(exists(&corev1.ServiceAccount{}, "web", "team-a")).To(BeTrue())
+		Expect(exists(&appsv1.Deployment{}, "web", "team-a")).To(BeTrue())
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "web-config", Namespace: "team-a"}, configMap)).To(Succeed())
+		Expect(configMap.Data["key"]).To(Equal("latest"))
+		Expect(exists(&corev1.ConfigMap{}, "other", "other")).To(BeFalse())
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(lines[1]).To(MatchRegexp(`^Namespace\s+<none>\s+team-a\s+default/trashed-deleted-namespace-team-a\s+restored$`))
+		Expect(lines[4]).To(MatchRegexp(`^Deployment\s+team-a\s+web\s+`))
+		Expect(lines[5]).To(Equal("Total: 4, succeeded: 4, failed: 0"))
+	})
+
+	It("should continue on errors and report the failed restores", func() {
+		Expect(k8sClient.Create(ctx, &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"},
+		})).To(Succeed())
+
+		err := bulkRestore(k8sClient, "default", bulkRestoreOptions{allFromNamespace: "team-a"}, restoreOptions{out: out})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("1 of 4 restores failed"))
+
+		Expect(out.String()).To(MatchRegexp(`ServiceAccount\s+team-a\s+web\s+team-a/trashed-deleted-serviceaccount-web\s+failed: .*already exists`))
+		Expect(exists(&appsv1.Deployment{}, "web", "team-a")).To(BeTrue())
+		Expect(exists(&moxv1alpha2.TrashedResource{}, "trashed-deleted-serviceaccount-web", "team-a")).To(BeTrue())
+	})
+
+	It("should restore by original name and deletion window", func() {
+		Expect(bulkRestore(k8sClient, "team-a", bulkRestoreOptions{
+			originalName:   "web-config",
+			deletedAfter:   now.Add(-4 * time.Hour),
+			deletedBefore:  now.Add(-time.Hour),
+			includeUpdates: true,
+		}, restoreOptions{out: out})).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "web-config", Namespace: "team-a"}, configMap)).To(Succeed())
+		Expect(configMap.Data["key"]).To(Equal("older"))
+		Expect(exists(&appsv1.Deployment{}, "web", "team-a")).To(BeFalse())
+	})
+
+	It("should only report on a dry run", func() {
+		Expect(bulkRestore(k8sClient, "team-a", bulkRestoreOptions{selector: moxv1alpha2.ActionLabel + "=delete"},
+			restoreOptions{dryRun: dryRunClient, out: out})).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("would be restored (dry run)"))
+		Expect(exists(&appsv1.Deployment{}, "web", "team-a")).To(BeFalse())
+		Expect(exists(&moxv1alpha2.TrashedResource{}, "trashed-deleted-deployment-web", "team-a")).To(BeTrue())
+	})
+
+	It("should reject a name together with a bulk mode via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "team-a"
+		configFlags.Namespace = &namespace
+
+		restoreCmd := restoreCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		restoreCmd.SetArgs([]string{"trashed-deleted-deployment-web", "--original-name", "web"})
+		restoreCmd.SetOut(GinkgoWriter)
+		restoreCmd.SetErr(GinkgoWriter)
+
+		err := restoreCmd.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("can not be used with"))
+	})
+
+	It("should restore with a deletion window via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "team-a"
+		configFlags.Namespace = &namespace
+
+		restoreCmd := restoreCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		restoreCmd.SetArgs([]string{"--deleted-after", "1h", "--deleted-before",
+			now.Add(time.Minute).UTC().Format(time.RFC3339)})
+		restoreCmd.SetOut(out)
+
+		Expect(restoreCmd.Execute()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(fmt.Sprintf("Total: %d, succeeded: %d", 3, 3)))
+		Expect(exists(&appsv1.Deployment{}, "web", "team-a")).To(BeTrue())
+	})
+})
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+)
+
+func describeCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe NAME",
+		Short: "Shows the details of a TrashedResource and the manifest of the original object",
+		Long:  `Example: kubectl trashedresources describe trashed-deleted-deployment-nginx-20260301-230159`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+			k8sClient, err := clientGetter(kubernetesConfigFlags)
+			if err != nil {
+				return err
+			}
+			return describeResource(k8sClient, args[0], trashNamespace(ns), cmd.OutOrStdout())
+		},
+	}
+}
+
+func describeResource(c client.Client, name, namespace string, out io.Writer) error {
+	if out == nil {
+		out = os.Stdout
+	}
+	trashed, err := trashedresources.NewTrashedResourceInteractor(c).Get(context.Background(), name, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to find TrashedResource %s/%s: %v", namespace, name, err)
+	}
+	if trashed == nil {
+		return fmt.Errorf("failed to find TrashedResource %s/%s: not found", namespace, name)
+	}
+
+	now := time.Now()
+	original, decodeErr := trashedresources.OriginalOf(trashed)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Name:\t%s\n", trashed.Name)
+	_, _ = fmt.Fprintf(w, "Namespace:\t%s\n", trashed.Namespace)
+	_, _ = fmt.Fprintf(w, "Labels:\t%s\n", formatMap(trashed.Labels))
+	_, _ = fmt.Fprintf(w, "Annotations:\t%s\n", formatMap(trashed.Annotations))
+	_, _ = fmt.Fprintf(w, "Created:\t%s (%s ago)\n", trashed.CreationTimestamp.UTC().Format(time.RFC3339),
+		duration.HumanDuration(now.Sub(trashed.CreationTimestamp.Time)))
+	if trashed.Spec.KeepUntil.IsZero() {
+		_, _ = fmt.Fprintf(w, "Keep Until:\t<invalid, never expires>\n")
+	} else {
+		_, _ = fmt.Fprintf(w, "Keep Until:\t%s (expires in %s)\n", trashed.Spec.KeepUntil.UTC().Format(time.RFC3339),
+			expiresIn(*trashed, now))
+	}
+	_, _ = fmt.Fprintf(w, "Action:\t%s\n", valueOrNone(string(trashedresources.ActionOf(trashed))))
+	_, _ = fmt.Fprintf(w, "Requested By:\t%s\n", valueOrNone(trashed.Spec.RequestedBy))
+	_, _ = fmt.Fprintf(w, "Phase:\t%s\n", valueOrNone(string(trashed.Status.Phase)))
+	_, _ = fmt.Fprintf(w, "Data Protection:\t%s\n", valueOrNone(string(trashed.Spec.DataProtection)))
+	_, _ = fmt.Fprintf(w, "Encoding:\t%s\n", valueOrNone(string(trashed.Spec.Encoding)))
+	if size := trashed.Spec.Size; size != nil {
+		_, _ = fmt.Fprintf(w, "Size:\t%d bytes (%d stored)\n", size.Original, size.Stored)
+	}
+	if external := trashed.Spec.External; external != nil {
+		_, _ = fmt.Fprintf(w, "Stored In:\t%s (key %s)\n", external.URL, external.Key)
+	}
+	_, _ = fmt.Fprintf(w, "Original:\t\n")
+	_, _ = fmt.Fprintf(w, "  API Version:\t%s\n", valueOrNone(originalAPIVersion(original)))
+	_, _ = fmt.Fprintf(w, "  Kind:\t%s\n", valueOrNone(original.Kind))
+	_, _ = fmt.Fprintf(w, "  Name:\t%s\n", valueOrNone(original.Name))
+	_, _ = fmt.Fprintf(w, "  Namespace:\t%s\n", valueOrNone(original.Namespace))
+	_, _ = fmt.Fprintf(w, "  UID:\t%s\n", valueOrNone(string(original.UID)))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(trashed.Status.Conditions) > 0 {
+		_, _ = fmt.Fprintln(out, "Conditions:")
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, condition := range trashed.Status.Conditions {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintln(out, "Original Manifest:")
+	if decodeErr != nil {
+		_, err = fmt.Fprintf(out, "  <failed to decode spec.data: %v>\n", decodeErr)
+		return err
+	}
+	data, err := trashedresources.TrashedData(context.Background(), trashed, storageBackendURL)
+	if err != nil {
+		_, err = fmt.Fprintf(out, "  <failed to read spec.data: %v>\n", err)
+		return err
+	}
+	manifest, err := originalManifest(string(data))
+	if err != nil {
+		return err
+	}
+	for line := range strings.Lines(string(manifest)) {
+		if _, err := fmt.Fprint(out, "  "+line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// originalManifest returns the decoded spec.data as YAML, as it was captured.
+func originalManifest(data string) ([]byte, error) {
+	object := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(data), 4096).Decode(object); err != nil {
+		return nil, fmt.Errorf("failed to decode resource data: %v", err)
+	}
+	manifest, err := goyaml.Marshal(object.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to print the manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+func originalAPIVersion(original moxv1alpha2.OriginalObject) string {
+	if original.Group == "" {
+		return original.Version
+	}
+	return original.Group + "/" + original.Version
+}
+
+func formatMap(values map[string]string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
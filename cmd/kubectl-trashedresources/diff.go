@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+)
+
+const (
+	diffModeUnified  = "unified"
+	diffModeSemantic = "semantic"
+)
+
+// diffOptions changes how the diff is printed.
+type diffOptions struct {
+	// mode is unified (line based YAML diff) or semantic (changed fields by path).
+	mode string
+	out  io.Writer
+}
+
+func (o diffOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+func diffCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := diffOptions{}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff NAME [OTHER]",
+		Short: "Shows the changes between a TrashedResource and the live object, or between two TrashedResources",
+		Long: `Example: kubectl trashedresources diff trashed-updated-configmap-app-config-20260301-230159
+or kubectl trashedresources diff trashed-updated-configmap-app-config-20260301-230159 trashed-updated-configmap-app-config-20260302-101500 --mode semantic`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			if opts.mode != diffModeUnified && opts.mode != diffModeSemantic {
+				return fmt.Errorf("invalid --mode value %q, must be %s or %s", opts.mode, diffModeUnified, diffModeSemantic)
+			}
+			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+
+			k8sClient, err := clientGetter(kubernetesConfigFlags)
+			if err != nil {
+				return err
+			}
+
+			other := ""
+			if len(args) == 2 {
+				other = args[1]
+			}
+			return diffResource(k8sClient, args[0], other, trashNamespace(ns), opts)
+		},
+	}
+
+	diffCmd.Flags().StringVar(&opts.mode, "mode", diffModeUnified,
+		"Must be unified (line based YAML diff) or semantic (changed fields by path)")
+
+	return diffCmd
+}
+
+// diffResource prints the changes from the object captured by the TrashedResource name to the live
+// object or, when other is set, to the object captured by the TrashedResource other.
+func diffResource(c client.Client, name, other, namespace string, opts diffOptions) error {
+	ctx := context.Background()
+
+	from, err := getTrashedObject(ctx, c, name, namespace)
+	if err != nil {
+		return err
+	}
+	fromLabel := "trashedresource/" + name
+
+	var to *unstructured.Unstructured
+	var toLabel string
+	if other != "" {
+		to, err = getTrashedObject(ctx, c, other, namespace)
+		if err != nil {
+			return err
+		}
+		if objectKey(from) != objectKey(to) {
+			return fmt.Errorf("TrashedResources %s and %s captured different objects (%s and %s)",
+				name, other, objectKey(from), objectKey(to))
+		}
+		toLabel = "trashedresource/" + other
+	} else {
+		to, err = getLiveObject(ctx, c, from)
+		if err != nil {
+			return err
+		}
+		toLabel = "live/" + objectKey(from)
+		if to == nil {
+			_, _ = fmt.Fprintf(opts.writer(), "Live object %s not found\n", objectKey(from))
+			to = &unstructured.Unstructured{Object: map[string]any{}}
+		}
+	}
+
+	if opts.mode == diffModeSemantic {
+		return printSemanticDiff(from, to, opts.writer())
+	}
+	return printUnifiedDiff(from, fromLabel, to, toLabel, opts.writer())
+}
+
+// getTrashedObject returns the object captured by the TrashedResource, without the fields managed by the cluster.
+func getTrashedObject(ctx context.Context, c client.Client, name, namespace string) (*unstructured.Unstructured, error) {
+	trashed := &moxv1alpha2.TrashedResource{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, trashed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TrashedResource %s/%s: %v", namespace, name, err)
+	}
+	return decodeTrashedObject(trashed)
+}
+
+// getLiveObject returns the live object with the GVK, name and namespace of the captured one, without
+// the fields managed by the cluster, or nil when it does not exist.
+func getLiveObject(ctx context.Context, c client.Client, captured *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(captured.GroupVersionKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(captured), live)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the live resource %s: %v", objectKey(captured), err)
+	}
+	cleanClusterManagedFields(live)
+	return live, nil
+}
+
+func objectKey(object *unstructured.Unstructured) string {
+	if object.GetNamespace() == "" {
+		return object.GetKind() + "/" + object.GetName()
+	}
+	return object.GetKind() + "/" + object.GetNamespace() + "/" + object.GetName()
+}
+
+func printUnifiedDiff(from *unstructured.Unstructured, fromLabel string, to *unstructured.Unstructured,
+	toLabel string, out io.Writer) error {
+	fromYAML, err := goyaml.Marshal(from.Object)
+	if err != nil {
+		return fmt.Errorf("failed to print the manifest: %v", err)
+	}
+	toYAML, err := goyaml.Marshal(to.Object)
+	if err != nil {
+		return fmt.Errorf("failed to print the manifest: %v", err)
+	}
+	if len(to.Object) == 0 {
+		toYAML = nil
+	}
+
+	return difflib.WriteUnifiedDiff(out, difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fromYAML)),
+		B:        difflib.SplitLines(string(toYAML)),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  3,
+	})
+}
+
+// printSemanticDiff prints one line per changed field, by path: + for added, - for removed and ~ for changed.
+func printSemanticDiff(from, to *unstructured.Unstructured, out io.Writer) error {
+	fromFields, toFields := map[string]any{}, map[string]any{}
+	flattenFields("", from.Object, fromFields)
+	flattenFields("", to.Object, toFields)
+
+	paths := make([]string, 0, len(fromFields)+len(toFields))
+	for path := range fromFields {
+		paths = append(paths, path)
+	}
+	for path := range toFields {
+		if _, found := fromFields[path]; !found {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fromValue, inFrom := fromFields[path]
+		toValue, inTo := toFields[path]
+		var err error
+		switch {
+		case !inFrom:
+			_, err = fmt.Fprintf(out, "+ %s: %v\n", path, toValue)
+		case !inTo:
+			_, err = fmt.Fprintf(out, "- %s: %v\n", path, fromValue)
+		case fmt.Sprint(fromValue) != fmt.Sprint(toValue):
+			_, err = fmt.Fprintf(out, "~ %s: %v -> %v\n", path, fromValue, toValue)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenFields stores every scalar (and empty map or list) of the value by its path, like spec.containers[0].image.
+func flattenFields(path string, value any, fields map[string]any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if len(typed) == 0 && path != "" {
+			fields[path] = "{}"
+			return
+		}
+		for key, child := range typed {
+			childPath := key
+			if strings.ContainsAny(key, ".[]") {
+				childPath = fmt.Sprintf("[%q]", key)
+				if path != "" {
+					childPath = path + childPath
+				}
+			} else if path != "" {
+				childPath = path + "." + key
+			}
+			flattenFields(childPath, child, fields)
+		}
+	case []any:
+		if len(typed) == 0 {
+			fields[path] = "[]"
+			return
+		}
+		for i, child := range typed {
+			flattenFields(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	default:
+		fields[path] = typed
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("kubectl-trashedresources diff", func() {
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+	const ns = "default"
+
+	newTrashedConfigMap := func(name, value string) *moxv1alpha2.TrashedResource {
+		return newTrashedFixture(name, ns, moxv1alpha2.ActionUpdate, 0, fmt.Sprintf(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+  resourceVersion: "10"
+  uid: 6f1c1a0e-0000-0000-0000-000000000000
+data:
+  key: %s
+`, value))
+	}
+
+	BeforeEach(func() {
+		k8sClient = newTestClientBuilder().Build()
+		out = &bytes.Buffer{}
+
+		Expect(k8sClient.Create(ctx, newTrashedConfigMap("trashed-first", "first"))).To(Succeed())
+		Expect(k8sClient.Create(ctx, newTrashedConfigMap("trashed-second", "second"))).To(Succeed())
+		Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: ns},
+			Data:       map[string]string{"key": "live", "added": "later"},
+		})).To(Succeed())
+	})
+
+	It("should print a unified diff against the live object without cluster managed fields", func() {
+		Expect(diffResource(k8sClient, "trashed-first", "", ns, diffOptions{mode: diffModeUnified, out: out})).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("--- trashedresource/trashed-first"))
+		Expect(out.String()).To(ContainSubstring("+++ live/ConfigMap/default/app-config"))
+		Expect(out.String()).To(ContainSubstring("-  key: first"))
+		Expect(out.String()).To(ContainSubstring("+  key: live"))
+		Expect(out.String()).To(ContainSubstring("+  added: later"))
+		Expect(out.String()).NotTo(ContainSubstring("resourceVersion"))
+		Expect(out.String()).NotTo(ContainSubstring("uid"))
+	})
+
+	It("should print a semantic diff against the live object", func() {
+		Expect(diffResource(k8sClient, "trashed-first", "", ns, diffOptions{mode: diffModeSemantic, out: out})).To(Succeed())
+
+		Expect(out.String()).To(Equal("+ data.added: later\n~ data.key: first -> live\n"))
+	})
+
+	It("should diff two TrashedResources of the same object", func() {
+		Expect(diffResource(k8sClient, "trashed-first", "trashed-second", ns, diffOptions{mode: diffModeSemantic, out: out})).To(Succeed())
+
+		Expect(out.String()).To(Equal("~ data.key: first -> second\n"))
+	})
+
+	It("should refuse to diff TrashedResources of different objects", func() {
+		other := newTrashedConfigMap("trashed-other", "other")
+		other.Spec.Data = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other-config\n  namespace: default\n"
+		Expect(k8sClient.Create(ctx, other)).To(Succeed())
+
+		err := diffResource(k8sClient, "trashed-first", "trashed-other", ns, diffOptions{mode: diffModeSemantic, out: out})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("different objects"))
+	})
+
+	It("should report a live object that no longer exists", func() {
+		Expect(k8sClient.Delete(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: ns},
+		})).To(Succeed())
+
+		Expect(diffResource(k8sClient, "trashed-first", "", ns, diffOptions{mode: diffModeSemantic, out: out})).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("Live object ConfigMap/default/app-config not found"))
+		Expect(out.String()).To(ContainSubstring("- data.key: first"))
+	})
+
+	It("should reject an invalid mode via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := ns
+		configFlags.Namespace = &namespace
+
+		diffCmd := diffCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		diffCmd.SetArgs([]string{"trashed-first", "--mode", "side-by-side"})
+		diffCmd.SetOut(GinkgoWriter)
+		diffCmd.SetErr(GinkgoWriter)
+
+		err := diffCmd.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid --mode value"))
+	})
+})
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+)
+
+// historyOptions changes how the revisions of an object are printed.
+type historyOptions struct {
+	// diff prints the changes between consecutive revisions, and from the latest one to the live object.
+	diff bool
+	// mode is the diff mode, unified or semantic.
+	mode string
+	out  io.Writer
+}
+
+func (o historyOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+func historyCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := historyOptions{}
+
+	historyCmd := &cobra.Command{
+		Use:   "history KIND/NAME",
+		Short: "Lists the revisions captured from an object, oldest first",
+		Long: `Example: kubectl trashedresources history configmap/app-config
+or kubectl trashedresources history configmap/app-config --diff --mode semantic`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			if opts.mode != diffModeUnified && opts.mode != diffModeSemantic {
+				return fmt.Errorf("invalid --mode value %q, must be %s or %s", opts.mode, diffModeUnified, diffModeSemantic)
+			}
+			kind, name, found := strings.Cut(args[0], "/")
+			if !found || kind == "" || name == "" || strings.Contains(name, "/") {
+				return fmt.Errorf("invalid object %q, must be KIND/NAME", args[0])
+			}
+			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+
+			k8sClient, err := clientGetter(kubernetesConfigFlags)
+			if err != nil {
+				return err
+			}
+			return historyOf(k8sClient, kind, name, ns, opts)
+		},
+	}
+
+	historyCmd.Flags().BoolVar(&opts.diff, "diff", false,
+		"Print the changes between consecutive revisions, and from the latest one to the live object")
+	historyCmd.Flags().StringVar(&opts.mode, "mode", diffModeUnified,
+		"Diff mode, must be unified (line based YAML diff) or semantic (changed fields by path)")
+
+	return historyCmd
+}
+
+// historyOf prints the revisions captured from the object kind/name of the namespace, oldest first.
+func historyOf(c client.Client, kind, name, namespace string, opts historyOptions) error {
+	ctx := context.Background()
+	out := opts.writer()
+
+	// In the vault namespace the revisions are selected by the original hash, as in the original namespace.
+	list, err := trashedresources.NewTrashedResourceInteractor(c).List(ctx, trashNamespace(namespace))
+	if err != nil {
+		return fmt.Errorf("failed to list TrashedResources: %v", err)
+	}
+	revisions := revisionsOf(list.Items, kind, name, namespace)
+	if len(revisions) == 0 {
+		_, _ = fmt.Fprintf(out, "No revisions found for %s/%s\n", kind, name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REVISION\tTRASHEDRESOURCE\tACTION\tCAPTURED")
+	for i, trashed := range revisions {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, trashed.Name,
+			valueOrNone(string(trashedresources.ActionOf(&trashed))),
+			trashed.CreationTimestamp.UTC().Format(time.RFC3339))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !opts.diff {
+		return nil
+	}
+
+	objects := make([]*unstructured.Unstructured, 0, len(revisions))
+	for _, trashed := range revisions {
+		object, err := decodeTrashedObject(&trashed)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, object)
+	}
+	for i := 1; i < len(objects); i++ {
+		_, _ = fmt.Fprintf(out, "\n# revision %d -> %d\n", i, i+1)
+		if err := printRevisionDiff(objects[i-1], "trashedresource/"+revisions[i-1].Name,
+			objects[i], "trashedresource/"+revisions[i].Name, opts); err != nil {
+			return err
+		}
+	}
+
+	latest := objects[len(objects)-1]
+	live, err := getLiveObject(ctx, c, latest)
+	if err != nil {
+		return err
+	}
+	if live == nil {
+		_, _ = fmt.Fprintf(out, "\n# revision %d -> live: live object %s not found\n", len(objects), objectKey(latest))
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "\n# revision %d -> live\n", len(objects))
+	return printRevisionDiff(latest, "trashedresource/"+revisions[len(revisions)-1].Name,
+		live, "live/"+objectKey(latest), opts)
+}
+
+func printRevisionDiff(from *unstructured.Unstructured, fromLabel string, to *unstructured.Unstructured,
+	toLabel string, opts historyOptions) error {
+	if opts.mode == diffModeSemantic {
+		return printSemanticDiff(from, to, opts.writer())
+	}
+	return printUnifiedDiff(from, fromLabel, to, toLabel, opts.writer())
+}
+
+// revisionsOf returns the TrashedResources captured from the object kind/name of the namespace, oldest first.
+// They are matched by the original hash label or, when created before it, by the original object. Cluster
+// scoped objects have no namespace, so they match too.
+func revisionsOf(items []moxv1alpha2.TrashedResource, kind, name, namespace string) []moxv1alpha2.TrashedResource {
+	hashes := []string{trashedresources.OriginalHash(kind, namespace, name), trashedresources.OriginalHash(kind, "", name)}
+	revisions := []moxv1alpha2.TrashedResource{}
+	for _, trashed := range items {
+		if labelHash, found := trashed.Labels[moxv1alpha2.OriginalHashLabel]; found {
+			if slices.Contains(hashes, labelHash) {
+				revisions = append(revisions, trashed)
+			}
+			continue
+		}
+		original, err := trashedresources.OriginalOf(&trashed)
+		if err == nil && strings.EqualFold(original.Kind, kind) && original.Name == name &&
+			(original.Namespace == namespace || original.Namespace == "") {
+			revisions = append(revisions, trashed)
+		}
+	}
+
+	slices.SortStableFunc(revisions, func(a, b moxv1alpha2.TrashedResource) int {
+		return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+	})
+	return revisions
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"strings"
+	"time"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("kubectl-trashedresources history", func() {
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+
+	newTrashed := func(name string, action moxv1alpha2.TrashedAction, age time.Duration, labels map[string]string,
+		manifest string) *moxv1alpha2.TrashedResource {
+		trashed := newTrashedFixture(name, "team-a", action, age, manifest)
+		maps.Copy(trashed.Labels, labels)
+		return trashed
+	}
+
+	configMap := func(name, value string) string {
+		return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: team-a\ndata:\n  key: " +
+			value + "\n"
+	}
+
+	BeforeEach(func() {
+		k8sClient = newTestClientBuilder().Build()
+		out = &bytes.Buffer{}
+
+		hashLabel := map[string]string{
+			moxv1alpha2.OriginalHashLabel: trashedresources.OriginalHash("ConfigMap", "team-a", "cfg"),
+		}
+		for _, trashed := range []*moxv1alpha2.TrashedResource{
+			newTrashed("trashed-updated-configmap-cfg-2", moxv1alpha2.ActionUpdate, 2*time.Hour, hashLabel,
+				configMap("cfg", "v2")),
+			// Created before the original hash label
+			newTrashed("trashed-updated-configmap-cfg-1", moxv1alpha2.ActionUpdate, 3*time.Hour, nil,
+				configMap("cfg", "v1")),
+			newTrashed("trashed-updated-configmap-cfg-3", moxv1alpha2.ActionUpdate, time.Hour, hashLabel,
+				configMap("cfg", "v3")),
+			newTrashed("trashed-updated-configmap-other", moxv1alpha2.ActionUpdate, time.Hour, nil,
+				configMap("other", "other")),
+		} {
+			Expect(k8sClient.Create(ctx, trashed)).To(Succeed())
+		}
+		Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "team-a"},
+			Data:       map[string]string{"key": "live"},
+		})).To(Succeed())
+	})
+
+	It("should list the revisions of an object oldest first", func() {
+		Expect(historyOf(k8sClient, "configmap", "cfg", "team-a", historyOptions{mode: diffModeUnified, out: out})).
+			To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(4))
+		Expect(lines[0]).To(MatchRegexp(`^REVISION\s+TRASHEDRESOURCE\s+ACTION\s+CAPTURED$`))
+		Expect(lines[1]).To(MatchRegexp(`^1\s+trashed-updated-configmap-cfg-1\s+update\s+`))
+		Expect(lines[2]).To(MatchRegexp(`^2\s+trashed-updated-configmap-cfg-2\s+update\s+`))
+		Expect(lines[3]).To(MatchRegexp(`^3\s+trashed-updated-configmap-cfg-3\s+update\s+`))
+	})
+
+	It("should print the changes between consecutive revisions and the live object", func() {
+		Expect(historyOf(k8sClient, "ConfigMap", "cfg", "team-a", historyOptions{diff: true, mode: diffModeSemantic, out: out})).
+			To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("# revision 1 -> 2\n~ data.key: v1 -> v2\n"))
+		Expect(out.String()).To(ContainSubstring("# revision 2 -> 3\n~ data.key: v2 -> v3\n"))
+		Expect(out.String()).To(ContainSubstring("# revision 3 -> live\n~ data.key: v3 -> live\n"))
+	})
+
+	It("should report an object without revisions", func() {
+		Expect(historyOf(k8sClient, "Secret", "cfg", "team-a", historyOptions{mode: diffModeUnified, out: out})).
+			To(Succeed())
+		Expect(out.String()).To(Equal("No revisions found for Secret/cfg\n"))
+	})
+
+	It("should reject an object without a kind via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "team-a"
+		configFlags.Namespace = &namespace
+
+		historyCmd := historyCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		historyCmd.SetArgs([]string{"cfg"})
+		historyCmd.SetOut(GinkgoWriter)
+		historyCmd.SetErr(GinkgoWriter)
+
+		err := historyCmd.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must be KIND/NAME"))
+	})
+
+	It("should print the unified diff via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "team-a"
+		configFlags.Namespace = &namespace
+
+		historyCmd := historyCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		historyCmd.SetArgs([]string{"configmap/cfg", "--diff"})
+		historyCmd.SetOut(out)
+
+		Expect(historyCmd.Execute()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("--- trashedresource/trashed-updated-configmap-cfg-1\n"))
+		Expect(out.String()).To(ContainSubstring("+++ live/ConfigMap/team-a/cfg\n"))
+		Expect(out.String()).To(ContainSubstring("-  key: v3\n+  key: live\n"))
+	})
+})
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+	utils "trashed-resources/internal/utils"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// listOptions filters and formats the listed TrashedResources. Zero values do not filter.
+type listOptions struct {
+	// kind of the original object, case insensitive.
+	kind string
+	// originalName is the name of the original object.
+	originalName string
+	// action is delete or update.
+	action string
+	// olderThan and newerThan filter by the age of the TrashedResource.
+	olderThan time.Duration
+	newerThan time.Duration
+	// expiringWithin keeps the TrashedResources deleted in less than this duration.
+	expiringWithin time.Duration
+	allNamespaces  bool
+	// output is table, json or yaml.
+	output string
+	out    io.Writer
+}
+
+func (o listOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+func listCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := listOptions{}
+	var olderThan, newerThan, expiringWithin string
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Lists TrashedResources with the kind, name and action of the original object",
+		Long: `Example: kubectl trashedresources list --kind Deployment --action delete
+or kubectl trashedresources list --all-namespaces --expiring-within 1h -o yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			if err := validateOutput(opts.output); err != nil {
+				return err
+			}
+			if opts.action != "" && opts.action != string(moxv1alpha2.ActionDelete) && opts.action != string(moxv1alpha2.ActionUpdate) {
+				return fmt.Errorf("invalid --action value %q, must be %s or %s", opts.action,
+					moxv1alpha2.ActionDelete, moxv1alpha2.ActionUpdate)
+			}
+			var err error
+			if opts.olderThan, err = parseDurationFlag("older-than", olderThan); err != nil {
+				return err
+			}
+			if opts.newerThan, err = parseDurationFlag("newer-than", newerThan); err != nil {
+				return err
+			}
+			if opts.expiringWithin, err = parseDurationFlag("expiring-within", expiringWithin); err != nil {
+				return err
+			}
+
+			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+			k8sClient, err := clientGetter(kubernetesConfigFlags)
+			if err != nil {
+				return err
+			}
+			return listResources(k8sClient, ns, opts)
+		},
+	}
+
+	listCmd.Flags().StringVar(&opts.kind, "kind", "", "Kind of the original object (e.g. Deployment)")
+	listCmd.Flags().StringVar(&opts.originalName, "original-name", "", "Name of the original object")
+	listCmd.Flags().StringVar(&opts.action, "action", "", "Captured action, delete or update")
+	listCmd.Flags().StringVar(&olderThan, "older-than", "", "Only TrashedResources older than this duration (e.g. 30m, 12h, 2d)")
+	listCmd.Flags().StringVar(&newerThan, "newer-than", "", "Only TrashedResources newer than this duration (e.g. 30m, 12h, 2d)")
+	listCmd.Flags().StringVar(&expiringWithin, "expiring-within", "",
+		"Only TrashedResources deleted by the controller within this duration (e.g. 1h)")
+	listCmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "List the TrashedResources of all namespaces")
+	listCmd.Flags().StringVarP(&opts.output, "output", "o", outputTable, "Must be table, json or yaml")
+
+	return listCmd
+}
+
+func validateOutput(output string) error {
+	switch output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid --output value %q, must be %s, %s or %s", output, outputTable, outputJSON, outputYAML)
+}
+
+// parseDurationFlag parses a duration flag, accepting days like the retention of the ConfigMap (e.g. 2d12h).
+func parseDurationFlag(flag, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := utils.ParseRetention(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s value: %v", flag, err)
+	}
+	return parsed, nil
+}
+
+func listResources(c client.Client, namespace string, opts listOptions) error {
+	if opts.allNamespaces {
+		namespace = ""
+	}
+	list := &moxv1alpha2.TrashedResourceList{}
+	if err := c.List(context.Background(), list, trashListOptions(namespace)...); err != nil {
+		return fmt.Errorf("failed to list TrashedResources: %v", err)
+	}
+
+	now := time.Now()
+	items := filterTrashedResources(list.Items, opts, now)
+
+	switch opts.output {
+	case outputJSON, outputYAML:
+		filtered := &moxv1alpha2.TrashedResourceList{Items: items}
+		filtered.APIVersion = moxv1alpha2.GroupVersion.String()
+		filtered.Kind = "TrashedResourceList"
+		return printObject(filtered, opts.output, opts.writer())
+	default:
+		return printTrashedResourcesTable(items, opts.allNamespaces, now, opts.writer())
+	}
+}
+
+// filterTrashedResources returns the TrashedResources that match every filter of the options.
+func filterTrashedResources(items []moxv1alpha2.TrashedResource, opts listOptions, now time.Time) []moxv1alpha2.TrashedResource {
+	filtered := []moxv1alpha2.TrashedResource{}
+	for _, trashed := range items {
+		original, _ := trashedresources.OriginalOf(&trashed)
+		age := now.Sub(trashed.CreationTimestamp.Time)
+		switch {
+		case opts.kind != "" && !strings.EqualFold(original.Kind, opts.kind):
+			continue
+		case opts.originalName != "" && original.Name != opts.originalName:
+			continue
+		case opts.action != "" && string(trashedresources.ActionOf(&trashed)) != opts.action:
+			continue
+		case opts.olderThan > 0 && age < opts.olderThan:
+			continue
+		case opts.newerThan > 0 && age > opts.newerThan:
+			continue
+		case opts.expiringWithin > 0 &&
+			(trashed.Spec.KeepUntil.IsZero() || trashed.Spec.KeepUntil.Sub(now) > opts.expiringWithin):
+			continue
+		}
+		filtered = append(filtered, trashed)
+	}
+	return filtered
+}
+
+func printTrashedResourcesTable(items []moxv1alpha2.TrashedResource, allNamespaces bool, now time.Time, out io.Writer) error {
+	if len(items) == 0 {
+		_, err := fmt.Fprintln(out, "No TrashedResources found")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	header := "NAME\tKIND\tORIGINAL NAME\tACTION\tPHASE\tAGE\tEXPIRES IN"
+	if allNamespaces && vaultNamespace != "" {
+		// Every TrashedResource is in the vault namespace
+		header = "ORIGINAL NAMESPACE\t" + header
+	} else if allNamespaces {
+		header = "NAMESPACE\t" + header
+	}
+	_, _ = fmt.Fprintln(w, header)
+	for _, trashed := range items {
+		original, _ := trashedresources.OriginalOf(&trashed)
+		row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
+			trashed.Name,
+			valueOrNone(original.Kind),
+			valueOrNone(original.Name),
+			valueOrNone(string(trashedresources.ActionOf(&trashed))),
+			valueOrNone(string(trashed.Status.Phase)),
+			duration.HumanDuration(now.Sub(trashed.CreationTimestamp.Time)),
+			expiresIn(trashed, now),
+		)
+		if allNamespaces && vaultNamespace != "" {
+			row = valueOrNone(original.Namespace) + "\t" + row
+		} else if allNamespaces {
+			row = trashed.Namespace + "\t" + row
+		}
+		_, _ = fmt.Fprintln(w, row)
+	}
+	return w.Flush()
+}
+
+func expiresIn(trashed moxv1alpha2.TrashedResource, now time.Time) string {
+	if trashed.Spec.KeepUntil.IsZero() {
+		return "<never>"
+	}
+	remaining := trashed.Spec.KeepUntil.Sub(now)
+	if remaining <= 0 {
+		return "<expired>"
+	}
+	return duration.HumanDuration(remaining)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// printObject prints the object as indented JSON or as YAML.
+func printObject(object runtime.Object, output string, out io.Writer) error {
+	if output == outputJSON {
+		content, err := json.MarshalIndent(object, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to print the output: %v", err)
+		}
+		_, err = fmt.Fprintln(out, string(content))
+		return err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return fmt.Errorf("failed to print the output: %v", err)
+	}
+	manifest, err := goyaml.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to print the output: %v", err)
+	}
+	_, err = out.Write(manifest)
+	return err
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("kubectl-trashedresources list and describe", func() {
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+
+	newTrashed := func(name, namespace, kind, originalName string, action moxv1alpha2.TrashedAction,
+		age, keepFor time.Duration) *moxv1alpha2.TrashedResource {
+		trashed := newTrashedFixture(name, namespace, action, age,
+			"apiVersion: v1\nkind: "+kind+"\nmetadata:\n  name: "+originalName+"\n  namespace: "+namespace+"\n")
+		trashed.Spec.KeepUntil = metav1.NewTime(trashed.CreationTimestamp.Add(keepFor))
+		return trashed
+	}
+
+	BeforeEach(func() {
+		k8sClient = newTestClientBuilder().Build()
+		out = &bytes.Buffer{}
+
+		for _, trashed := range []*moxv1alpha2.TrashedResource{
+			newTrashed("trashed-deleted-configmap-app-config", "default", "ConfigMap", "app-config",
+				moxv1alpha2.ActionDelete, 2*time.Hour, 24*time.Hour),
+			newTrashed("trashed-updated-configmap-app-config", "default", "ConfigMap", "app-config",
+				moxv1alpha2.ActionUpdate, 10*time.Minute, 30*time.Minute),
+			newTrashed("trashed-deleted-secret-token", "default", "Secret", "token",
+				moxv1alpha2.ActionDelete, 5*time.Minute, 24*time.Hour),
+			newTrashed("trashed-deleted-secret-other", "other", "Secret", "other",
+				moxv1alpha2.ActionDelete, 5*time.Minute, 24*time.Hour),
+		} {
+			Expect(k8sClient.Create(ctx, trashed)).To(Succeed())
+		}
+	})
+
+	listNames := func(opts listOptions, namespace string) []string {
+		opts.output = outputJSON
+		opts.out = out
+		Expect(listResources(k8sClient, namespace, opts)).To(Succeed())
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(json.Unmarshal(out.Bytes(), list)).To(Succeed())
+		names := []string{}
+		for _, trashed := range list.Items {
+			names = append(names, trashed.Name)
+		}
+		return names
+	}
+
+	It("should filter by kind, case insensitive", func() {
+		Expect(listNames(listOptions{kind: "secret"}, "default")).To(ConsistOf("trashed-deleted-secret-token"))
+	})
+
+	It("should filter by original name and action", func() {
+		Expect(listNames(listOptions{originalName: "app-config", action: "update"}, "default")).
+			To(ConsistOf("trashed-updated-configmap-app-config"))
+	})
+
+	It("should filter by age", func() {
+		Expect(listNames(listOptions{olderThan: time.Hour}, "default")).
+			To(ConsistOf("trashed-deleted-configmap-app-config"))
+		out.Reset()
+		Expect(listNames(listOptions{newerThan: time.Hour}, "default")).
+			To(ConsistOf("trashed-updated-configmap-app-config", "trashed-deleted-secret-token"))
+	})
+
+	It("should filter the TrashedResources expiring within a duration", func() {
+		Expect(listNames(listOptions{expiringWithin: time.Hour}, "default")).
+			To(ConsistOf("trashed-updated-configmap-app-config"))
+	})
+
+	It("should list all namespaces", func() {
+		Expect(listNames(listOptions{kind: "Secret", allNamespaces: true}, "default")).
+			To(ConsistOf("trashed-deleted-secret-token", "trashed-deleted-secret-other"))
+	})
+
+	It("should print a table with the original kind, name and action", func() {
+		Expect(listResources(k8sClient, "default", listOptions{kind: "ConfigMap", output: outputTable, out: out})).To(Succeed())
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(3))
+		Expect(string(lines[0])).To(MatchRegexp(`^NAME\s+KIND\s+ORIGINAL NAME\s+ACTION\s+PHASE\s+AGE\s+EXPIRES IN$`))
+		Expect(out.String()).To(MatchRegexp(`trashed-deleted-configmap-app-config\s+ConfigMap\s+app-config\s+delete\s+<none>\s+120m`))
+	})
+
+	It("should print yaml", func() {
+		Expect(listResources(k8sClient, "default", listOptions{kind: "Secret", output: outputYAML, out: out})).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("kind: TrashedResourceList"))
+		Expect(out.String()).To(ContainSubstring("name: trashed-deleted-secret-token"))
+	})
+
+	It("should reject an invalid output via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "default"
+		configFlags.Namespace = &namespace
+
+		listCmd := listCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		listCmd.SetArgs([]string{"-o", "wide"})
+		listCmd.SetOut(GinkgoWriter)
+		listCmd.SetErr(GinkgoWriter)
+
+		err := listCmd.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid --output value"))
+	})
+
+	It("should list with days in the age flags via CLI", func() {
+		configFlags := genericclioptions.NewConfigFlags(true)
+		namespace := "default"
+		configFlags.Namespace = &namespace
+
+		listCmd := listCmd(configFlags, func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		})
+		listCmd.SetArgs([]string{"--expiring-within", "1d", "--action", "delete"})
+		listCmd.SetOut(out)
+
+		Expect(listCmd.Execute()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("trashed-deleted-configmap-app-config"))
+		Expect(out.String()).To(ContainSubstring("trashed-deleted-secret-token"))
+		Expect(out.String()).NotTo(ContainSubstring("trashed-updated-configmap-app-config"))
+	})
+
+	It("should describe a TrashedResource with its original manifest", func() {
+		Expect(describeResource(k8sClient, "trashed-deleted-secret-token", "default", out)).To(Succeed())
+
+		Expect(out.String()).To(MatchRegexp(`Name:\s+trashed-deleted-secret-token`))
+		Expect(out.String()).To(MatchRegexp(`Action:\s+delete`))
+		Expect(out.String()).To(MatchRegexp(`Requested By:\s+<none>`))
+		Expect(out.String()).To(MatchRegexp(`  Kind:\s+Secret`))
+		Expect(out.String()).To(ContainSubstring("Original Manifest:\n"))
+		Expect(out.String()).To(ContainSubstring("  kind: Secret\n"))
+		Expect(out.String()).To(ContainSubstring("    name: token\n"))
+	})
+
+	It("should fail to describe a TrashedResource that does not exist", func() {
+		err := describeResource(k8sClient, "non-existent", "default", out)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to find TrashedResource"))
+	})
+})
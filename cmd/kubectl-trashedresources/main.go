@@ -1,35 +1,56 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
 	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v2"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
-	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
 )
 
 type clientGetterFunc func(flags *genericclioptions.ConfigFlags) (client.Client, error)
 
 var (
 	scheme = runtime.NewScheme()
+	// storageBackendURL overrides the storage backend URL recorded in the TrashedResources whose
+	// captured object is stored externally, e.g. to read a file:// backend mounted elsewhere.
+	storageBackendURL string
+	// vaultNamespace is the namespace holding the TrashedResources of every namespace, when the controller
+	// is configured with a vaultNamespace.
+	vaultNamespace string
 )
 
 func init() {
 	// Register Kubernetes core scheme and your CRD
-	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
+	_ = eventsv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
 	_ = metav1.AddMetaToScheme(scheme)
 }
 
@@ -40,21 +61,46 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use:   "kubectl-trashedresources",
 		Short: "Plugin to manage TrashedResources",
-		Long:  `CLI tool to restore and prune TrashedResources in the cluster.`,
+		Long:  `CLI tool to list, describe, restore, prune and diff TrashedResources in the cluster.`,
 	}
 
 	// Add global k8s flags (e.g. -n namespace)
 	kubernetesConfigFlags.AddFlags(rootCmd.PersistentFlags())
+	rootCmd.PersistentFlags().StringVar(&storageBackendURL, "storage-backend", "",
+		"Storage backend URL to read the captured objects stored externally, instead of the one recorded in them")
+	rootCmd.PersistentFlags().StringVar(&vaultNamespace, "vault-namespace", "",
+		"Namespace holding the TrashedResources of every namespace, read from the controller ConfigMap when not set")
+
+	// The commands reading the TrashedResources of a namespace look for them in the vault of the controller
+	vaultClient := withVaultNamespace(getClient, func() bool {
+		return rootCmd.PersistentFlags().Changed("vault-namespace")
+	})
 
 	// --- RESTORE Command ---
-	restoreCmd := restoreCmd(kubernetesConfigFlags, getClient)
+	restoreCmd := restoreCmd(kubernetesConfigFlags, vaultClient)
 	rootCmd.AddCommand(restoreCmd)
 
 	// --- PRUNE Command Delete  by age  or name
-	pruneCmd := pruneCmd(kubernetesConfigFlags, getClient)
+	pruneCmd := pruneCmd(kubernetesConfigFlags, vaultClient)
 
 	rootCmd.AddCommand(pruneCmd)
 
+	// --- DIFF Command compares the captured object with the live one or with another capture
+	rootCmd.AddCommand(diffCmd(kubernetesConfigFlags, vaultClient))
+
+	// --- LIST and DESCRIBE Commands
+	rootCmd.AddCommand(listCmd(kubernetesConfigFlags, vaultClient))
+	rootCmd.AddCommand(describeCmd(kubernetesConfigFlags, vaultClient))
+
+	// --- AT Command reconstructs a namespace at a point in time
+	rootCmd.AddCommand(atCmd(kubernetesConfigFlags, getClient))
+
+	// --- HISTORY Command lists the revisions captured from an object
+	rootCmd.AddCommand(historyCmd(kubernetesConfigFlags, vaultClient))
+
+	// --- RELEASE-FINALIZERS Command removes the trash protection finalizer before uninstalling the controller
+	rootCmd.AddCommand(releaseFinalizersCmd(kubernetesConfigFlags, getClient))
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -72,14 +118,91 @@ func getClient(flags *genericclioptions.ConfigFlags) (client.Client, error) {
 	return client.New(cfg, client.Options{Scheme: scheme})
 }
 
+// restoreOptions changes where and how a TrashedResource is restored.
+type restoreOptions struct {
+	// toNamespace restores the object in another namespace.
+	toNamespace string
+	// asName restores the object under another name.
+	asName string
+	// dryRun is none, client or server.
+	dryRun string
+	// output prints the restored manifest when set to yaml.
+	output string
+	// force retries the rollback of an "updated" TrashedResource when the live object changes.
+	force bool
+	out   io.Writer
+}
+
+func (o restoreOptions) validate() error {
+	switch o.dryRun {
+	case "", dryRunNone, dryRunClient, dryRunServer:
+	default:
+		return fmt.Errorf("invalid --dry-run value %q, must be %s, %s or %s", o.dryRun, dryRunNone, dryRunClient, dryRunServer)
+	}
+	if o.output != "" && o.output != "yaml" {
+		return fmt.Errorf("invalid --output value %q, only yaml is supported", o.output)
+	}
+	return nil
+}
+
+// printf prints the progress messages, omitted when the manifest is printed.
+func (o restoreOptions) printf(format string, a ...any) {
+	if o.output == "" {
+		_, _ = fmt.Fprintf(o.writer(), format, a...)
+	}
+}
+
+func (o restoreOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+const (
+	dryRunNone   = "none"
+	dryRunClient = "client"
+	dryRunServer = "server"
+)
+
 func restoreCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := restoreOptions{}
+	bulkOpts := bulkRestoreOptions{}
+	var deletedAfter, deletedBefore string
 
-	return &cobra.Command{
+	restoreCmd := &cobra.Command{
 		Use:   "restore [NAME]",
 		Short: "Restores a deleted resource from a TrashedResource",
-		Args:  cobra.ExactArgs(1),
+		Long: `Example: kubectl trashedresources restore trashed-deleted-deployment-nginx-20260301-230159
+or kubectl trashedresources restore trashed-deleted-deployment-nginx-20260301-230159 --as-name nginx-investigation
+or kubectl trashedresources restore trashed-deleted-deployment-nginx-20260301-230159 --to-namespace debug --dry-run=client -o yaml
+or kubectl trashedresources restore --all-from-namespace team-a --deleted-after 2026-03-01T23:00:00Z`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			resourceName := args[0]
+			opts.out = cmd.OutOrStdout()
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			var err error
+			if bulkOpts.deletedAfter, err = parseTimeFlag("deleted-after", deletedAfter); err != nil {
+				return err
+			}
+			if bulkOpts.deletedBefore, err = parseTimeFlag("deleted-before", deletedBefore); err != nil {
+				return err
+			}
+			if bulkOpts.enabled() {
+				if len(args) > 0 {
+					return fmt.Errorf("a TrashedResource name can not be used with --all-from-namespace, " +
+						"--deleted-after, --deleted-before, --selector or --original-name")
+				}
+				if opts.asName != "" || opts.output != "" {
+					return fmt.Errorf("--as-name and --output can only be used to restore a single TrashedResource")
+				}
+			} else if len(args) == 0 {
+				return fmt.Errorf("either a TrashedResource name or one of --all-from-namespace, " +
+					"--deleted-after, --deleted-before, --selector or --original-name is required")
+			}
+
 			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
 			if err != nil {
 				return err
@@ -90,55 +213,100 @@ func restoreCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGett
 				return err
 			}
 
-			return restoreResource(k8sClient, resourceName, ns)
+			if bulkOpts.enabled() {
+				return bulkRestore(k8sClient, ns, bulkOpts, opts)
+			}
+			return restoreResource(k8sClient, args[0], trashNamespace(ns), opts)
 		},
 	}
+
+	restoreCmd.Flags().StringVar(&opts.toNamespace, "to-namespace", "", "Restore the object in this namespace instead of the original one")
+	restoreCmd.Flags().StringVar(&opts.asName, "as-name", "", "Restore the object with this name instead of the original one")
+	restoreCmd.Flags().StringVar(&opts.dryRun, "dry-run", dryRunNone,
+		"Must be none, client or server. With client the object is only printed, with server it is submitted without being persisted")
+	restoreCmd.Flags().Lookup("dry-run").NoOptDefVal = dryRunClient
+	restoreCmd.Flags().StringVarP(&opts.output, "output", "o", "", "Print the restored manifest. Only yaml is supported")
+	restoreCmd.Flags().BoolVar(&opts.force, "force", false,
+		"Roll back an updated resource over its latest version when it changes during the restore")
+
+	// Bulk restore
+	restoreCmd.Flags().StringVar(&bulkOpts.allFromNamespace, "all-from-namespace", "",
+		"Restore every object captured in this namespace, and the namespace itself, searching all namespaces")
+	restoreCmd.Flags().StringVar(&deletedAfter, "deleted-after", "",
+		"Restore the objects captured after this time (RFC3339, or a duration before now like 2h)")
+	restoreCmd.Flags().StringVar(&deletedBefore, "deleted-before", "",
+		"Restore the objects captured before this time (RFC3339, or a duration before now like 2h)")
+	restoreCmd.Flags().StringVarP(&bulkOpts.selector, "selector", "l", "", "Restore the TrashedResources matching this label selector")
+	restoreCmd.Flags().StringVar(&bulkOpts.originalName, "original-name", "", "Restore the objects with this name")
+	restoreCmd.Flags().BoolVarP(&bulkOpts.allNamespaces, "all-namespaces", "A", false,
+		"Search the TrashedResources of all namespaces in a bulk restore")
+	restoreCmd.Flags().BoolVar(&bulkOpts.includeUpdates, "include-updates", false,
+		"Restore the captures of updated objects too in a bulk restore, by default only deleted objects are restored")
+
+	return restoreCmd
+}
+
+// pruneOptions selects the TrashedResources to prune. Zero values do not filter.
+type pruneOptions struct {
+	// name of the TrashedResource.
+	name string
+	// selector is a label selector of the TrashedResources (e.g. mox.app.br/policy=production-secrets).
+	selector string
+	// kind, originalName, action and olderThan filter like the list command.
+	kind          string
+	originalName  string
+	action        string
+	olderThan     time.Duration
+	allNamespaces bool
+	// dryRun prints the summary without deleting anything.
+	dryRun bool
+	// yes skips the confirmation prompt.
+	yes bool
+	in  io.Reader
+	out io.Writer
+}
+
+func (o pruneOptions) hasFilter() bool {
+	return o.name != "" || o.selector != "" || o.kind != "" || o.originalName != "" || o.action != "" || o.olderThan > 0
+}
+
+func (o pruneOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
 }
 
 func pruneCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := pruneOptions{}
 	var olderThan string
 
 	pruneCmd := &cobra.Command{
 		Use:   "prune",
-		Short: "Deletes TrashedResources older than a specified duration",
+		Short: "Deletes TrashedResources by name, age, labels, kind or action",
 		Long: `Example: kubectl trashedresources prune --older-than 1d
-or kubectl trashedresources prune trashed-deployment-myapp-12345`,
+or kubectl trashedresources prune trashed-deployment-myapp-12345
+or kubectl trashedresources prune --all-namespaces --kind Secret --action update --dry-run
+or kubectl trashedresources prune -l mox.app.br/policy=production-secrets --yes`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := ""
+			opts.in = cmd.InOrStdin()
+			opts.out = cmd.OutOrStdout()
 			if len(args) > 0 {
-				name = args[0]
-			}
-
-			if name == "" && olderThan == "" {
-				return fmt.Errorf("either a resource name or the --older-than flag is required")
+				opts.name = args[0]
 			}
-
-			hasArgumentDuration := false
-			duration := time.Duration(0)
 			var err error
-
-			if olderThan != "" {
-				duration, err = time.ParseDuration(olderThan)
-				hasArgumentDuration = true
-				if err != nil {
-					// Try to support "1d" which native Go time.ParseDuration sometimes doesn't support depending on version/lib,
-					// but here we assume standard Go format (1h, 10m, 24h).
-					// If days are needed, use 24h, 48h, etc.
-					if strings.HasSuffix(olderThan, "d") {
-						daysStr := strings.TrimSuffix(olderThan, "d")
-						days, err := time.ParseDuration(daysStr + "h")
-						if err == nil {
-							duration = days * 24
-						} else {
-							return fmt.Errorf("invalid time format: %v", err)
-						}
-
-					} else {
-						return fmt.Errorf("invalid time format (use 10m, 5h, 24h): %v", err)
-					}
-				}
+			if opts.olderThan, err = parseDurationFlag("older-than", olderThan); err != nil {
+				return err
+			}
+			if !opts.hasFilter() {
+				return fmt.Errorf("either a resource name or one of --older-than, --selector, --kind, --action or --original-name is required")
 			}
+			if opts.action != "" && opts.action != string(moxv1alpha2.ActionDelete) && opts.action != string(moxv1alpha2.ActionUpdate) {
+				return fmt.Errorf("invalid --action value %q, must be %s or %s", opts.action,
+					moxv1alpha2.ActionDelete, moxv1alpha2.ActionUpdate)
+			}
+
 			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
 			if err != nil {
 				// If namespace is not specified, assume all (depending on config) or default
@@ -150,120 +318,384 @@ or kubectl trashedresources prune trashed-deployment-myapp-12345`,
 				return err
 			}
 
-			return pruneResources(k8sClient, ns, duration, hasArgumentDuration, name)
+			return pruneResources(k8sClient, ns, opts)
 		},
 	}
 
-	pruneCmd.Flags().StringVar(&olderThan, "older-than", "", "Duration to consider old (e.g. 14m, 11h, 24h)")
+	pruneCmd.Flags().StringVar(&olderThan, "older-than", "", "Duration to consider old (e.g. 14m, 11h, 24h, 2d)")
+	pruneCmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Label selector of the TrashedResources (e.g. mox.app.br/action=update)")
+	pruneCmd.Flags().StringVar(&opts.kind, "kind", "", "Kind of the original object (e.g. Deployment)")
+	pruneCmd.Flags().StringVar(&opts.originalName, "original-name", "", "Name of the original object")
+	pruneCmd.Flags().StringVar(&opts.action, "action", "", "Captured action, delete or update")
+	pruneCmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "Prune the TrashedResources of all namespaces")
+	pruneCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only print what would be deleted")
+	pruneCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Delete without asking for confirmation")
 
 	return pruneCmd
 }
 
-func restoreResource(c client.Client, name, namespace string) error {
+func restoreResource(c client.Client, name, namespace string, opts restoreOptions) error {
 	ctx := context.Background()
 
 	// 1. Get the TrashedResource
-	trashed := &moxv1alpha1.TrashedResource{}
+	trashed := &moxv1alpha2.TrashedResource{}
 	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, trashed)
 	if err != nil {
 		return fmt.Errorf("failed to find TrashedResource %s/%s: %v", namespace, name, err)
 	}
 
-	fmt.Printf("Restoring resource from: %s\n", trashed.Name)
+	opts.printf("Restoring resource from: %s\n", trashed.Name)
 
-	// 2. Convert spec.data (YAML string) to Unstructured
-	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(trashed.Spec.Data), 4096)
-	restoredObject := &unstructured.Unstructured{}
-	if err := decoder.Decode(restoredObject); err != nil {
-		return fmt.Errorf("failed to decode resource data: %v", err)
+	// 2. Convert spec.data (YAML string), decrypted when protected, to Unstructured
+	restoredObject, err := decodeRestorableObject(ctx, c, trashed)
+	if err != nil {
+		return setRestoreStatus(c, trashed, nil, err)
 	}
+	originalNamespace, originalName := restoredObject.GetNamespace(), restoredObject.GetName()
 
-	// Before creating, we must clear metadata fields that are managed by the cluster.
-	restoredObject.SetUID("")
-	restoredObject.SetResourceVersion("")
-	restoredObject.SetGeneration(0)
-	restoredObject.SetCreationTimestamp(metav1.Time{})
-	restoredObject.SetOwnerReferences(nil)
-	restoredObject.SetManagedFields(nil)
-	unstructured.RemoveNestedField(restoredObject.Object, "status")
-	getOriginalName := restoredObject.GetAnnotations()["OriginalName"]
-	if getOriginalName != "" {
-		restoredObject.SetName(getOriginalName)
-	} else {
-		restoredObject.SetName(restoredObject.GetName())
+	// 3. Restore it in another namespace and/or with another name when requested
+	if opts.toNamespace != "" {
+		if originalNamespace == "" {
+			return fmt.Errorf("%s %s is cluster scoped and can not be restored in a namespace",
+				restoredObject.GetKind(), originalName)
+		}
+		restoredObject.SetNamespace(opts.toNamespace)
+	}
+	if opts.asName != "" {
+		restoredObject.SetName(opts.asName)
 	}
 
-	err = c.Create(ctx, restoredObject)
+	if opts.dryRun == dryRunClient {
+		opts.printf("Resource %s %s/%s would be restored (dry run)\n",
+			restoredObject.GetKind(),
+			restoredObject.GetNamespace(),
+			restoredObject.GetName())
+		return printManifest(restoredObject, opts)
+	}
+
+	inPlace := restoredObject.GetNamespace() == originalNamespace && restoredObject.GetName() == originalName
+	if inPlace && trashedresources.ActionOf(trashed) == moxv1alpha2.ActionUpdate {
+		err = rollbackLiveObject(ctx, c, restoredObject, opts)
+	} else {
+		err = createRestoredObject(ctx, c, restoredObject, opts)
+	}
 	if err != nil {
-		if errors.IsAlreadyExists(err) {
-			return fmt.Errorf("resource %s %s/%s already exists",
-				restoredObject.GetKind(),
-				restoredObject.GetNamespace(),
-				restoredObject.GetName(),
-			)
+		if opts.dryRun == dryRunServer {
+			return err
 		}
-		return fmt.Errorf("failed to create restored resource: %v", err)
+		return setRestoreStatus(c, trashed, restoredObject, err)
+	}
+
+	if opts.dryRun == dryRunServer {
+		opts.printf("Resource %s %s/%s would be restored (server dry run)\n",
+			restoredObject.GetKind(),
+			restoredObject.GetNamespace(),
+			restoredObject.GetName())
+		return printManifest(restoredObject, opts)
 	}
 
-	fmt.Printf("Success! Resource %s %s/%s restored.\n",
+	opts.printf("Success! Resource %s %s/%s restored.\n",
 		restoredObject.GetKind(),
 		restoredObject.GetNamespace(),
 		restoredObject.GetName())
+	_ = setRestoreStatus(c, trashed, restoredObject, nil)
+
+	// The TrashedResource is kept when the object was restored side by side with the original one.
+	if !inPlace {
+		opts.printf("TrashedResource %s/%s kept, the object was restored under a new name or namespace\n", namespace, name)
+		return printManifest(restoredObject, opts)
+	}
 	err = c.Delete(ctx, trashed, client.PropagationPolicy(metav1.DeletePropagationBackground))
 	if err != nil {
-		fmt.Printf("Warning: failed to delete TrashedResource %s/%s: %v. You should manually delete it\n",
+		opts.printf("Warning: failed to delete TrashedResource %s/%s: %v. You should manually delete it\n",
 			namespace,
 			name,
 			err,
 		)
 	}
+	return printManifest(restoredObject, opts)
+}
+
+// createRestoredObject creates the restored object, failing when it already exists.
+func createRestoredObject(ctx context.Context, c client.Client, restoredObject *unstructured.Unstructured,
+	opts restoreOptions) error {
+	createOpts := []client.CreateOption{}
+	if opts.dryRun == dryRunServer {
+		createOpts = append(createOpts, client.DryRunAll)
+	}
+	err := c.Create(ctx, restoredObject, createOpts...)
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			err = fmt.Errorf("resource %s %s/%s already exists",
+				restoredObject.GetKind(),
+				restoredObject.GetNamespace(),
+				restoredObject.GetName(),
+			)
+		} else {
+			err = fmt.Errorf("failed to create restored resource: %v", err)
+		}
+		return err
+	}
 	return nil
 }
 
-func pruneResources(c client.Client, namespace string, olderThan time.Duration, hasArgumentDuration bool,
-	name string) error {
+// rollbackLiveObject replaces the live object with the snapshot of an "updated" TrashedResource,
+// using the resourceVersion of the live object. When the live object changes during the rollback
+// the update fails with a conflict, unless --force is set, which retries over the latest version.
+// The object is created when it no longer exists.
+func rollbackLiveObject(ctx context.Context, c client.Client, restoredObject *unstructured.Unstructured,
+	opts restoreOptions) error {
+	updateOpts := []client.UpdateOption{}
+	if opts.dryRun == dryRunServer {
+		updateOpts = append(updateOpts, client.DryRunAll)
+	}
+
+	rollback := func() error {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(restoredObject.GroupVersionKind())
+		err := c.Get(ctx, client.ObjectKeyFromObject(restoredObject), live)
+		if errors.IsNotFound(err) {
+			restoredObject.SetResourceVersion("")
+			return createRestoredObject(ctx, c, restoredObject, opts)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get the live resource: %v", err)
+		}
+		restoredObject.SetResourceVersion(live.GetResourceVersion())
+		// The live object keeps its owners, they are cleared from the snapshot with the other cluster managed fields.
+		restoredObject.SetOwnerReferences(live.GetOwnerReferences())
+		if err := c.Update(ctx, restoredObject, updateOpts...); err != nil {
+			if errors.IsConflict(err) {
+				return err
+			}
+			return fmt.Errorf("failed to roll back resource: %v", err)
+		}
+		return nil
+	}
+
+	var err error
+	if opts.force {
+		err = retry.RetryOnConflict(retry.DefaultRetry, rollback)
+	} else {
+		err = rollback()
+	}
+	// The resourceVersion is only needed by the update, it is not part of the restored manifest.
+	restoredObject.SetResourceVersion("")
+	if errors.IsConflict(err) {
+		return fmt.Errorf("resource %s %s/%s was changed during the rollback, use --force to roll back over the latest version",
+			restoredObject.GetKind(),
+			restoredObject.GetNamespace(),
+			restoredObject.GetName())
+	}
+	return err
+}
+
+// decodeTrashedObject decodes spec.data of the TrashedResource, decompressed or read from the storage
+// backend, and clears the metadata fields managed by the cluster. The sensitive values of a TrashedResource
+// with data protection are redacted.
+func decodeTrashedObject(trashed *moxv1alpha2.TrashedResource) (*unstructured.Unstructured, error) {
+	manifest, err := trashedresources.TrashedData(context.Background(), trashed, storageBackendURL)
+	if err != nil {
+		return nil, err
+	}
+	return decodeManifest(string(manifest))
+}
+
+// decodeRestorableObject decodes the whole captured object, decrypted or read from the data Secret
+// of the TrashedResource, and clears the metadata fields managed by the cluster.
+func decodeRestorableObject(ctx context.Context, c client.Client,
+	trashed *moxv1alpha2.TrashedResource) (*unstructured.Unstructured, error) {
+	manifest, err := trashedresources.RestorableManifest(ctx, c, trashed, storageBackendURL)
+	if err != nil {
+		return nil, err
+	}
+	return decodeManifest(string(manifest))
+}
+
+func decodeManifest(manifest string) (*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	restoredObject := &unstructured.Unstructured{}
+	if err := decoder.Decode(restoredObject); err != nil {
+		return nil, fmt.Errorf("failed to decode resource data: %v", err)
+	}
+
+	cleanClusterManagedFields(restoredObject)
+	getOriginalName := restoredObject.GetAnnotations()["OriginalName"]
+	if getOriginalName != "" {
+		restoredObject.SetName(getOriginalName)
+	}
+	return restoredObject, nil
+}
+
+// cleanClusterManagedFields clears the metadata fields that are managed by the cluster and the status.
+func cleanClusterManagedFields(object *unstructured.Unstructured) {
+	object.SetUID("")
+	object.SetResourceVersion("")
+	object.SetGeneration(0)
+	object.SetCreationTimestamp(metav1.Time{})
+	object.SetDeletionTimestamp(nil)
+	object.SetDeletionGracePeriodSeconds(nil)
+	object.SetOwnerReferences(nil)
+	object.SetManagedFields(nil)
+	unstructured.RemoveNestedField(object.Object, "status")
+}
+
+// printManifest prints the object as YAML when -o yaml is set.
+func printManifest(object *unstructured.Unstructured, opts restoreOptions) error {
+	if opts.output != "yaml" {
+		return nil
+	}
+	manifest, err := goyaml.Marshal(object.Object)
+	if err != nil {
+		return fmt.Errorf("failed to print the manifest: %v", err)
+	}
+	_, err = opts.writer().Write(manifest)
+	return err
+}
+
+// setRestoreStatus records the result of the restore in the phase and in the Restored condition
+// of the TrashedResource, and in a Restored or RestoreFailed Event, and returns restoreErr. The restored
+// object can be nil. Failing to update the status or to create the Event only prints a warning.
+func setRestoreStatus(c client.Client, trashed *moxv1alpha2.TrashedResource, restored *unstructured.Unstructured,
+	restoreErr error) error {
+	condition := metav1.Condition{
+		Type:               moxv1alpha2.ConditionRestored,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Restored",
+		Message:            "The object was restored",
+		ObservedGeneration: trashed.Generation,
+	}
+	trashed.Status.Phase = moxv1alpha2.PhaseRestored
+	if restoreErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RestoreFailed"
+		condition.Message = restoreErr.Error()
+		trashed.Status.Phase = moxv1alpha2.PhaseRestoreFailed
+	}
+	meta.SetStatusCondition(&trashed.Status.Conditions, condition)
+
+	if err := c.Status().Update(context.Background(), trashed); err != nil {
+		fmt.Printf("Warning: failed to update the status of TrashedResource %s/%s: %v\n",
+			trashed.Namespace,
+			trashed.Name,
+			err,
+		)
+	}
+
+	var related client.Object
+	if restored != nil {
+		related = restored
+	}
+	if err := trashedresources.RecordRestoreEvent(context.Background(), c, trashed, related, restoreErr); err != nil {
+		fmt.Printf("Warning: failed to create the restore Event of TrashedResource %s/%s: %v\n",
+			trashed.Namespace,
+			trashed.Name,
+			err,
+		)
+	}
+	return restoreErr
+}
+
+func pruneResources(c client.Client, namespace string, opts pruneOptions) error {
 	ctx := context.Background()
-	list := &moxv1alpha1.TrashedResourceList{}
+	out := opts.writer()
+	list := &moxv1alpha2.TrashedResourceList{}
 
-	opts := []client.ListOption{}
-	if namespace != "" {
-		opts = append(opts, client.InNamespace(namespace))
+	listOpts := []client.ListOption{}
+	if namespace != "" && !opts.allNamespaces {
+		listOpts = trashListOptions(namespace)
+	}
+	if opts.name != "" {
+		listOpts = append(listOpts, client.MatchingFields(map[string]string{"metadata.name": opts.name}))
 	}
-	ignoreAge := !hasArgumentDuration
-	if name != "" {
-		opts = append(opts, client.MatchingFields(map[string]string{"metadata.name": name}))
+	if opts.selector != "" {
+		selector, err := labels.Parse(opts.selector)
+		if err != nil {
+			return fmt.Errorf("invalid --selector value: %v", err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
 	}
 
-	if err := c.List(ctx, list, opts...); err != nil {
+	if err := c.List(ctx, list, listOpts...); err != nil {
 		return fmt.Errorf("failed to list TrashedResources: %v", err)
 	}
 
-	cutoffTime := time.Now().Add(-olderThan)
-	deletedCount := 0
+	now := time.Now()
+	if opts.olderThan != 0 {
+		_, _ = fmt.Fprintf(out, "Searching for TrashedResources created before %s (older-than %s)\n",
+			now.Add(-opts.olderThan).Format(time.DateTime),
+			opts.olderThan)
+	}
+	if opts.name != "" {
+		_, _ = fmt.Fprintf(out, "Searching for TrashedResources named as %s\n", opts.name)
+	}
 
-	if olderThan != 0 {
-		fmt.Printf("Searching for TrashedResources created before %s (older-than %s)\n",
-			cutoffTime.Format(time.DateTime),
-			olderThan)
+	toPrune := filterTrashedResources(list.Items, listOptions{
+		kind:         opts.kind,
+		originalName: opts.originalName,
+		action:       opts.action,
+		olderThan:    opts.olderThan,
+	}, now)
+	if len(toPrune) == 0 {
+		_, _ = fmt.Fprintln(out, "No TrashedResources to prune")
+		return nil
 	}
 
-	if name != "" {
-		fmt.Printf("Searching for TrashedResources named as %s\n", name)
+	if err := printPruneSummary(toPrune, out); err != nil {
+		return err
+	}
+	if opts.dryRun {
+		_, _ = fmt.Fprintf(out, "%d TrashedResources would be deleted (dry run)\n", len(toPrune))
+		return nil
+	}
+	if !opts.yes && !confirm(fmt.Sprintf("Delete %d TrashedResources?", len(toPrune)), opts.in, out) {
+		_, _ = fmt.Fprintln(out, "Aborted, nothing was deleted")
+		return nil
 	}
-	for _, tr := range list.Items {
-		// Check age based on TrashedResource CreationTimestamp
-		if tr.CreationTimestamp.Time.Before(cutoffTime) || ignoreAge {
 
-			fmt.Printf("Deleting %s/%s (Created at: %s)\n", tr.Namespace, tr.Name, tr.CreationTimestamp.Format(time.DateTime))
+	deletedCount := 0
+	for _, tr := range toPrune {
+		_, _ = fmt.Fprintf(out, "Deleting %s/%s (Created at: %s)\n", tr.Namespace, tr.Name, tr.CreationTimestamp.Format(time.DateTime))
 
-			if err := c.Delete(ctx, &tr); err != nil {
-				fmt.Printf("ERROR deleting %s: %v\n", tr.Name, err)
-			} else {
-				deletedCount++
-			}
+		if err := c.Delete(ctx, &tr); err != nil {
+			_, _ = fmt.Fprintf(out, "ERROR deleting %s: %v\n", tr.Name, err)
+		} else {
+			deletedCount++
 		}
 	}
-	fmt.Printf("Total deleted: %d\n", deletedCount)
+	_, _ = fmt.Fprintf(out, "Total deleted: %d\n", deletedCount)
 
 	return nil
 }
+
+// printPruneSummary prints how many TrashedResources are pruned by namespace and kind of the original object.
+func printPruneSummary(toPrune []moxv1alpha2.TrashedResource, out io.Writer) error {
+	type group struct{ namespace, kind string }
+	counts := map[group]int{}
+	for _, tr := range toPrune {
+		original, _ := trashedresources.OriginalOf(&tr)
+		counts[group{namespace: tr.Namespace, kind: valueOrNone(original.Kind)}]++
+	}
+	groups := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(groups, func(a, b group) int {
+		return cmp.Or(strings.Compare(a.namespace, b.namespace), strings.Compare(a.kind, b.kind))
+	})
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAMESPACE\tKIND\tCOUNT")
+	for _, g := range groups {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\n", g.namespace, g.kind, counts[g])
+	}
+	return w.Flush()
+}
+
+// confirm asks the question and returns true when the answer is y or yes.
+func confirm(question string, in io.Reader, out io.Writer) bool {
+	if in == nil {
+		in = os.Stdin
+	}
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
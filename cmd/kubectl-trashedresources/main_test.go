@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -8,18 +9,25 @@ import (
 	"testing"
 	"time"
 
-	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+	"trashed-resources/internal/storage"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	sigsyaml "sigs.k8s.io/yaml"
 )
 
 func TestKubectlTrashedResources(t *testing.T) {
@@ -27,22 +35,60 @@ func TestKubectlTrashedResources(t *testing.T) {
 	RunSpecs(t, "Kubectl TrashedResources Suite")
 }
 
+// testScheme has the kinds used by the plugin tests.
+var testScheme *runtime.Scheme
+
+var _ = BeforeSuite(func() {
+	testScheme = runtime.NewScheme()
+	Expect(moxv1alpha2.AddToScheme(testScheme)).To(Succeed())
+	Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+	Expect(eventsv1.AddToScheme(testScheme)).To(Succeed())
+	Expect(appsv1.AddToScheme(testScheme)).To(Succeed())
+})
+
+// newTestClientBuilder returns the fake client of the plugin tests, with the status subresource and the
+// metadata.name field index of the TrashedResources.
+func newTestClientBuilder() *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithStatusSubresource(&moxv1alpha2.TrashedResource{}).
+		WithIndex(&moxv1alpha2.TrashedResource{}, "metadata.name", func(o client.Object) []string {
+			return []string{o.GetName()}
+		})
+}
+
+// newTrashedFixture returns a TrashedResource created age ago, capturing manifest with the action. The
+// original reference is taken from the manifest.
+func newTrashedFixture(name, namespace string, action moxv1alpha2.TrashedAction, age time.Duration,
+	manifest string) *moxv1alpha2.TrashedResource {
+	original := &unstructured.Unstructured{}
+	Expect(sigsyaml.Unmarshal([]byte(manifest), &original.Object)).To(Succeed())
+	return &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Labels:            map[string]string{moxv1alpha2.ActionLabel: string(action)},
+		},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			Data: manifest,
+			Original: moxv1alpha2.OriginalReference{
+				APIVersion: original.GetAPIVersion(),
+				Kind:       original.GetKind(),
+				Name:       original.GetName(),
+				Namespace:  original.GetNamespace(),
+			},
+			Action: action,
+		},
+	}
+}
+
 var _ = Describe("kubectl-trashedresources plugin", func() {
 	var k8sClient client.Client
-	var testScheme *runtime.Scheme
 	ctx := context.Background()
 
 	BeforeEach(func() {
-		testScheme = runtime.NewScheme()
-		Expect(moxv1alpha1.AddToScheme(testScheme)).To(Succeed())
-		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
-
-		k8sClient = fake.NewClientBuilder().
-			WithScheme(testScheme).
-			WithIndex(&moxv1alpha1.TrashedResource{}, "metadata.name", func(o client.Object) []string {
-				return []string{o.GetName()}
-			}).
-			Build()
+		k8sClient = newTestClientBuilder().Build()
 	})
 
 	Context("when restoring a resource", func() {
@@ -62,12 +108,12 @@ data:
   key: value
 `, cmName, ns)
 
-			tr := &moxv1alpha1.TrashedResource{
+			tr := &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      trName,
 					Namespace: ns,
 				},
-				Spec: moxv1alpha1.TrashedResourceSpec{
+				Spec: moxv1alpha2.TrashedResourceSpec{
 					Data: cmYAML,
 				},
 			}
@@ -75,7 +121,7 @@ data:
 		})
 
 		It("should restore the object and delete the trashed resource", func() {
-			err := restoreResource(k8sClient, trName, ns)
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{out: GinkgoWriter})
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify ConfigMap was created
@@ -85,12 +131,21 @@ data:
 			Expect(restoredCM.Data["key"]).To(Equal("value"))
 
 			// Verify TrashedResource was deleted
-			err = k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			// Verify the Restored Event
+			events := &eventsv1.EventList{}
+			Expect(k8sClient.List(ctx, events, client.InNamespace(ns))).To(Succeed())
+			Expect(events.Items).To(HaveLen(1))
+			Expect(events.Items[0].Reason).To(Equal(trashedresources.EventReasonRestored))
+			Expect(events.Items[0].Regarding.Name).To(Equal(trName))
+			Expect(events.Items[0].Related.Kind).To(Equal("ConfigMap"))
+			Expect(events.Items[0].Related.Name).To(Equal(cmName))
 		})
 
 		It("should return an error if the trashed resource does not exist", func() {
-			err := restoreResource(k8sClient, "non-existent", ns)
+			err := restoreResource(k8sClient, "non-existent", ns, restoreOptions{out: GinkgoWriter})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to find TrashedResource"))
 		})
@@ -105,39 +160,343 @@ data:
 			}
 			Expect(k8sClient.Create(ctx, existingCM)).To(Succeed())
 
-			err := restoreResource(k8sClient, trName, ns)
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{out: GinkgoWriter})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("already exists"))
+
+			// Verify the failure is recorded in the TrashedResource status
+			tr := &moxv1alpha2.TrashedResource{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, tr)).To(Succeed())
+			Expect(tr.Status.Phase).To(Equal(moxv1alpha2.PhaseRestoreFailed))
+			Expect(tr.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", moxv1alpha2.ConditionRestored),
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Message", ContainSubstring("already exists")),
+			)))
+			events := &eventsv1.EventList{}
+			Expect(k8sClient.List(ctx, events, client.InNamespace(ns))).To(Succeed())
+			Expect(events.Items).To(ConsistOf(And(
+				HaveField("Type", corev1.EventTypeWarning),
+				HaveField("Reason", trashedresources.EventReasonRestoreFailed),
+				HaveField("Note", ContainSubstring("already exists")),
+			)))
+		})
+
+		It("should restore side by side with another name and namespace and keep the trashed resource", func() {
+			existingCM := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: ns}}
+			Expect(k8sClient.Create(ctx, existingCM)).To(Succeed())
+
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{
+				toNamespace: "debug",
+				asName:      "my-configmap-investigation",
+				out:         GinkgoWriter,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			restoredCM := &corev1.ConfigMap{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "my-configmap-investigation", Namespace: "debug"}, restoredCM)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restoredCM.Data["key"]).To(Equal("value"))
+
+			tr := &moxv1alpha2.TrashedResource{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, tr)).To(Succeed())
+			Expect(tr.Status.Phase).To(Equal(moxv1alpha2.PhaseRestored))
+		})
+
+		It("should not create anything on a client dry run and print the cleaned manifest", func() {
+			out := &bytes.Buffer{}
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{
+				asName: "preview",
+				dryRun: dryRunClient,
+				output: "yaml",
+				out:    out,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(ContainSubstring("name: preview"))
+			Expect(out.String()).To(ContainSubstring("key: value"))
+			Expect(out.String()).NotTo(ContainSubstring("resourceVersion"))
+			Expect(out.String()).NotTo(ContainSubstring("Restoring"))
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "preview", Namespace: ns}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			tr := &moxv1alpha2.TrashedResource{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, tr)).To(Succeed())
+			Expect(tr.Status.Phase).To(BeEmpty())
+		})
+
+		It("should keep the trashed resource on a server dry run", func() {
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{dryRun: dryRunServer, out: GinkgoWriter})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ns}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha2.TrashedResource{})).To(Succeed())
+		})
+
+		It("should refuse to restore a cluster scoped object in another namespace", func() {
+			tr := &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-ns-test", Namespace: ns},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team-a\n",
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+
+			err := restoreResource(k8sClient, "trashed-ns-test", ns, restoreOptions{toNamespace: "debug", out: GinkgoWriter})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cluster scoped"))
+		})
+	})
+
+	Context("when restoring a resource with data protection", func() {
+		const ns = "default"
+		secretYAML := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\n  namespace: default\ndata:\n  password: czNjcjN0\n"
+		redactedYAML := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\n  namespace: default\ndata:\n  password: REDACTED\n"
+
+		restoredPassword := func() string {
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "db", Namespace: ns}, secret)).To(Succeed())
+			return string(secret.Data["password"])
+		}
+
+		It("should restore the object from the data Secret", func() {
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-secret-db", Namespace: ns},
+				Data:       map[string][]byte{"manifest": []byte(secretYAML)},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-secret-db", Namespace: ns},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data:           redactedYAML,
+					DataProtection: moxv1alpha2.DataSecret,
+					DataSecretRef:  &moxv1alpha2.SecretKeyReference{Name: "trashed-deleted-secret-db", Key: "manifest"},
+				},
+			})).To(Succeed())
+
+			Expect(restoreResource(k8sClient, "trashed-deleted-secret-db", ns, restoreOptions{out: GinkgoWriter})).To(Succeed())
+			Expect(restoredPassword()).To(Equal("s3cr3t"))
+		})
+
+		It("should decrypt the object", func() {
+			key := bytes.Repeat([]byte{1}, 32)
+			keyRef := moxv1alpha2.SecretKeyReference{Namespace: "trashed-resources-system", Name: "key", Key: "key"}
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "trashed-resources-system"},
+				Data:       map[string][]byte{"key": key},
+			})).To(Succeed())
+			encrypted, err := trashedresources.EncryptData(key, keyRef, []byte(secretYAML))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-secret-db", Namespace: ns},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data:           redactedYAML,
+					DataProtection: moxv1alpha2.DataEncrypted,
+					Encrypted:      encrypted,
+				},
+			})).To(Succeed())
+
+			Expect(restoreResource(k8sClient, "trashed-deleted-secret-db", ns, restoreOptions{out: GinkgoWriter})).To(Succeed())
+			Expect(restoredPassword()).To(Equal("s3cr3t"))
+		})
+
+		It("should refuse to restore a redacted object", func() {
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-secret-db", Namespace: ns},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data:           redactedYAML,
+					DataProtection: moxv1alpha2.DataRedacted,
+				},
+			})).To(Succeed())
+
+			err := restoreResource(k8sClient, "trashed-deleted-secret-db", ns, restoreOptions{out: GinkgoWriter})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("redacted"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "db", Namespace: ns}, &corev1.Secret{})).NotTo(Succeed())
+		})
+	})
+
+	Context("when restoring a compressed or externally stored resource", func() {
+		const ns = "default"
+		configMapYAML := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: large\n  namespace: default\ndata:\n  key: value\n"
+
+		restoredValue := func() string {
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "large", Namespace: ns}, cm)).To(Succeed())
+			return cm.Data["key"]
+		}
+
+		It("should decompress the object", func() {
+			data, err := trashedresources.EncodeData([]byte(configMapYAML), moxv1alpha2.DataEncodingGzip)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-configmap-large", Namespace: ns},
+				Spec:       moxv1alpha2.TrashedResourceSpec{Data: data, Encoding: moxv1alpha2.DataEncodingGzip},
+			})).To(Succeed())
+
+			Expect(restoreResource(k8sClient, "trashed-deleted-configmap-large", ns, restoreOptions{out: GinkgoWriter})).To(Succeed())
+			Expect(restoredValue()).To(Equal("value"))
+		})
+
+		It("should read the object from the storage backend given by --storage-backend", func() {
+			root := GinkgoT().TempDir()
+			backend := storage.NewFilesystem(root)
+			Expect(backend.Put(ctx, "default/trashed-deleted-configmap-large", []byte(configMapYAML))).To(Succeed())
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-configmap-large", Namespace: ns},
+				Spec: moxv1alpha2.TrashedResourceSpec{External: &moxv1alpha2.ExternalData{
+					URL: "file:///var/lib/trashed-resources",
+					Key: "default/trashed-deleted-configmap-large",
+				}},
+			})).To(Succeed())
+
+			storageBackendURL = "file://" + root
+			DeferCleanup(func() { storageBackendURL = "" })
+			Expect(restoreResource(k8sClient, "trashed-deleted-configmap-large", ns, restoreOptions{out: GinkgoWriter})).To(Succeed())
+			Expect(restoredValue()).To(Equal("value"))
+		})
+	})
+
+	Context("when restoring an updated resource", func() {
+		const trName = "trashed-updated-configmap-my-configmap-20260301-230159"
+		const ns = "default"
+		const cmName = "my-configmap"
+
+		BeforeEach(func() {
+			tr := &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      trName,
+					Namespace: ns,
+				},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Action: moxv1alpha2.ActionUpdate,
+					Data: fmt.Sprintf(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: %s
+  resourceVersion: "10"
+data:
+  key: old-value
+`, cmName, ns),
+				},
+			}
+			Expect(k8sClient.Create(ctx, tr)).To(Succeed())
+		})
+
+		createLiveConfigMap := func() {
+			live := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: ns},
+				Data:       map[string]string{"key": "new-value", "added": "later"},
+			}
+			Expect(k8sClient.Create(ctx, live)).To(Succeed())
+		}
+
+		// conflictOnce makes the first update of the client fail with a conflict.
+		conflictOnce := func() client.Client {
+			conflicted := false
+			return interceptor.NewClient(k8sClient.(client.WithWatch), interceptor.Funcs{
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					if !conflicted {
+						conflicted = true
+						return errors.NewConflict(corev1.Resource("configmaps"), obj.GetName(), fmt.Errorf("changed"))
+					}
+					return c.Update(ctx, obj, opts...)
+				},
+			})
+		}
+
+		It("should roll the live object back and delete the trashed resource", func() {
+			createLiveConfigMap()
+
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{out: GinkgoWriter})
+			Expect(err).NotTo(HaveOccurred())
+
+			restoredCM := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ns}, restoredCM)).To(Succeed())
+			Expect(restoredCM.Data).To(Equal(map[string]string{"key": "old-value"}))
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha2.TrashedResource{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should create the object when it no longer exists", func() {
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{out: GinkgoWriter})
+			Expect(err).NotTo(HaveOccurred())
+
+			restoredCM := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ns}, restoredCM)).To(Succeed())
+			Expect(restoredCM.Data["key"]).To(Equal("old-value"))
+		})
+
+		It("should not change the live object on a server dry run", func() {
+			createLiveConfigMap()
+
+			err := restoreResource(k8sClient, trName, ns, restoreOptions{dryRun: dryRunServer, out: GinkgoWriter})
+			Expect(err).NotTo(HaveOccurred())
+
+			liveCM := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ns}, liveCM)).To(Succeed())
+			Expect(liveCM.Data["key"]).To(Equal("new-value"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha2.TrashedResource{})).To(Succeed())
+		})
+
+		It("should fail on a conflict without --force", func() {
+			createLiveConfigMap()
+
+			err := restoreResource(conflictOnce(), trName, ns, restoreOptions{out: GinkgoWriter})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--force"))
+
+			liveCM := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ns}, liveCM)).To(Succeed())
+			Expect(liveCM.Data["key"]).To(Equal("new-value"))
+
+			tr := &moxv1alpha2.TrashedResource{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, tr)).To(Succeed())
+			Expect(tr.Status.Phase).To(Equal(moxv1alpha2.PhaseRestoreFailed))
+		})
+
+		It("should retry on a conflict with --force", func() {
+			createLiveConfigMap()
+
+			err := restoreResource(conflictOnce(), trName, ns, restoreOptions{force: true, out: GinkgoWriter})
+			Expect(err).NotTo(HaveOccurred())
+
+			restoredCM := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ns}, restoredCM)).To(Succeed())
+			Expect(restoredCM.Data).To(Equal(map[string]string{"key": "old-value"}))
 		})
 	})
 
 	Context("when pruning resources", func() {
-		var oldResource, newResource, namedResource, otherNsResource *moxv1alpha1.TrashedResource
+		var oldResource, newResource, namedResource, otherNsResource *moxv1alpha2.TrashedResource
 
 		BeforeEach(func() {
 			// Create resources with different ages and namespaces
-			oldResource = &moxv1alpha1.TrashedResource{
+			oldResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "old-resource",
 					Namespace:         "default",
 					CreationTimestamp: metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
 				},
 			}
-			newResource = &moxv1alpha1.TrashedResource{
+			newResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "new-resource",
 					Namespace:         "default",
 					CreationTimestamp: metav1.Time{Time: time.Now().Add(-10 * time.Minute)},
 				},
 			}
-			namedResource = &moxv1alpha1.TrashedResource{
+			namedResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "special-name",
 					Namespace:         "default",
 					CreationTimestamp: metav1.Time{Time: time.Now().Add(-5 * time.Minute)},
 				},
 			}
-			otherNsResource = &moxv1alpha1.TrashedResource{
+			otherNsResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "other-ns-resource",
 					Namespace:         "other",
@@ -156,71 +515,159 @@ data:
 
 		It("should delete only resources older than the specified duration", func() {
 			// Prune resources older than 1 hour in all namespaces
-			err := pruneResources(k8sClient, "", 1*time.Hour, true, "")
+			err := pruneResources(k8sClient, "", pruneOptions{olderThan: 1 * time.Hour, yes: true, out: GinkgoWriter})
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify old resources are deleted
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldResource), &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherNsResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherNsResource), &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
 
 			// Verify new resources remain
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(newResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(newResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(namedResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(namedResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should delete only the resource with the specified name", func() {
 			// Prune by name, no age limit
-			err := pruneResources(k8sClient, "default", 0, false, "special-name")
+			err := pruneResources(k8sClient, "default", pruneOptions{name: "special-name", yes: true, out: GinkgoWriter})
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify named resource is deleted
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(namedResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(namedResource), &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
 
 			// Verify others remain
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(newResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(newResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherNsResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherNsResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should only prune resources within the specified namespace", func() {
 			// Prune resources older than 1 hour in 'default' namespace
-			err := pruneResources(k8sClient, "default", 1*time.Hour, true, "")
+			err := pruneResources(k8sClient, "default", pruneOptions{olderThan: 1 * time.Hour, yes: true, out: GinkgoWriter})
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify old resource in 'default' is deleted
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldResource), &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
 
 			// Verify new resource in 'default' remains
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(newResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(newResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify resource in 'other' namespace remains
-			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherNsResource), &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherNsResource), &moxv1alpha2.TrashedResource{})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should correctly parse 'd' for days in older-than flag", func() {
-			// This is testing the cobra command logic indirectly.
-			// The main.go has a special handling for 'd' suffix.
-			// Let's simulate that logic.
-			olderThan := "1d"
-			var duration time.Duration
-			if strings.HasSuffix(olderThan, "d") {
-				daysStr := strings.TrimSuffix(olderThan, "d")
-				days, err := time.ParseDuration(daysStr + "h")
-				Expect(err).NotTo(HaveOccurred())
-				duration = days * 24
-			}
+			duration, err := parseDurationFlag("older-than", "1d")
+			Expect(err).NotTo(HaveOccurred())
 			Expect(duration).To(Equal(24 * time.Hour))
+
+			duration, err = parseDurationFlag("older-than", "1d12h")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(duration).To(Equal(36 * time.Hour))
+		})
+	})
+
+	Context("when pruning resources with filters", func() {
+		var out *bytes.Buffer
+
+		newTrashed := func(name, namespace, kind string, action moxv1alpha2.TrashedAction) *moxv1alpha2.TrashedResource {
+			return newTrashedFixture(name, namespace, action, time.Hour,
+				"apiVersion: v1\nkind: "+kind+"\nmetadata:\n  name: app\n  namespace: "+namespace+"\n")
+		}
+
+		exists := func(name, namespace string) bool {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &moxv1alpha2.TrashedResource{})
+			return err == nil
+		}
+
+		BeforeEach(func() {
+			out = &bytes.Buffer{}
+			updatedSecret := newTrashed("trashed-updated-secret-app", "default", "Secret", moxv1alpha2.ActionUpdate)
+			updatedSecret.Labels["team"] = "payments"
+			Expect(k8sClient.Create(ctx, updatedSecret)).To(Succeed())
+			Expect(k8sClient.Create(ctx, newTrashed("trashed-deleted-secret-app", "default", "Secret", moxv1alpha2.ActionDelete))).To(Succeed())
+			Expect(k8sClient.Create(ctx, newTrashed("trashed-updated-configmap-app", "default", "ConfigMap", moxv1alpha2.ActionUpdate))).To(Succeed())
+			Expect(k8sClient.Create(ctx, newTrashed("trashed-updated-secret-app", "other", "Secret", moxv1alpha2.ActionUpdate))).To(Succeed())
+		})
+
+		It("should prune by kind and action in all namespaces and print a summary", func() {
+			err := pruneResources(k8sClient, "default", pruneOptions{
+				kind: "Secret", action: "update", allNamespaces: true, yes: true, out: out,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(exists("trashed-updated-secret-app", "default")).To(BeFalse())
+			Expect(exists("trashed-updated-secret-app", "other")).To(BeFalse())
+			Expect(exists("trashed-deleted-secret-app", "default")).To(BeTrue())
+			Expect(exists("trashed-updated-configmap-app", "default")).To(BeTrue())
+
+			Expect(out.String()).To(MatchRegexp(`NAMESPACE\s+KIND\s+COUNT\ndefault\s+Secret\s+1\nother\s+Secret\s+1\n`))
+			Expect(out.String()).To(ContainSubstring("Total deleted: 2"))
+		})
+
+		It("should prune by label selector and original name", func() {
+			err := pruneResources(k8sClient, "default", pruneOptions{
+				selector: "team=payments", originalName: "app", yes: true, out: out,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(exists("trashed-updated-secret-app", "default")).To(BeFalse())
+			Expect(exists("trashed-deleted-secret-app", "default")).To(BeTrue())
+			Expect(exists("trashed-updated-secret-app", "other")).To(BeTrue())
+		})
+
+		It("should reject an invalid label selector", func() {
+			err := pruneResources(k8sClient, "default", pruneOptions{selector: "team in (", yes: true, out: out})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid --selector value"))
+		})
+
+		It("should not delete anything on a dry run", func() {
+			err := pruneResources(k8sClient, "default", pruneOptions{action: "update", dryRun: true, out: out})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("2 TrashedResources would be deleted (dry run)"))
+			Expect(exists("trashed-updated-secret-app", "default")).To(BeTrue())
+			Expect(exists("trashed-updated-configmap-app", "default")).To(BeTrue())
+		})
+
+		It("should delete only after the confirmation", func() {
+			err := pruneResources(k8sClient, "default", pruneOptions{kind: "ConfigMap", in: strings.NewReader("n\n"), out: out})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(ContainSubstring("Delete 1 TrashedResources? [y/N]"))
+			Expect(out.String()).To(ContainSubstring("Aborted"))
+			Expect(exists("trashed-updated-configmap-app", "default")).To(BeTrue())
+
+			err = pruneResources(k8sClient, "default", pruneOptions{kind: "ConfigMap", in: strings.NewReader("yes\n"), out: out})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists("trashed-updated-configmap-app", "default")).To(BeFalse())
+		})
+
+		It("should ask for confirmation via CLI without --yes", func() {
+			configFlags := genericclioptions.NewConfigFlags(true)
+			namespace := "default"
+			configFlags.Namespace = &namespace
+			mockClientGetter := func(flags *genericclioptions.ConfigFlags) (client.Client, error) { return k8sClient, nil }
+
+			pruneCmd := pruneCmd(configFlags, mockClientGetter)
+			pruneCmd.SetArgs([]string{"-l", "team=payments"})
+			pruneCmd.SetIn(strings.NewReader(""))
+			pruneCmd.SetOut(out)
+			Expect(pruneCmd.Execute()).To(Succeed())
+
+			Expect(out.String()).To(ContainSubstring("Aborted"))
+			Expect(exists("trashed-updated-secret-app", "default")).To(BeTrue())
 		})
 	})
 
@@ -337,12 +784,12 @@ data:
   key: value
 `, cmName, ns)
 
-			tr := &moxv1alpha1.TrashedResource{
+			tr := &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      trName,
 					Namespace: ns,
 				},
-				Spec: moxv1alpha1.TrashedResourceSpec{
+				Spec: moxv1alpha2.TrashedResourceSpec{
 					Data: cmYAML,
 				},
 			}
@@ -365,7 +812,7 @@ data:
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify TrashedResource was deleted
-			err = k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
 
 			// Verify ConfigMap was created
@@ -374,20 +821,58 @@ data:
 			Expect(err).NotTo(HaveOccurred())
 			Expect(restoredCM.Data["key"]).To(Equal("value"))
 		})
+
+		It("should reject an invalid dry-run value", func() {
+			configFlags := genericclioptions.NewConfigFlags(true)
+			namespace := ns
+			configFlags.Namespace = &namespace
+
+			mockClientGetter := func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+				return k8sClient, nil
+			}
+
+			restoreCmd := restoreCmd(configFlags, mockClientGetter)
+			restoreCmd.SetArgs([]string{trName, "--dry-run=everything"})
+			restoreCmd.SetOut(GinkgoWriter)
+			restoreCmd.SetErr(GinkgoWriter)
+
+			err := restoreCmd.Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid --dry-run value"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: trName, Namespace: ns}, &moxv1alpha2.TrashedResource{})).To(Succeed())
+		})
+
+		It("should print the manifest with --dry-run and -o yaml", func() {
+			configFlags := genericclioptions.NewConfigFlags(true)
+			namespace := ns
+			configFlags.Namespace = &namespace
+
+			mockClientGetter := func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+				return k8sClient, nil
+			}
+
+			out := &bytes.Buffer{}
+			restoreCmd := restoreCmd(configFlags, mockClientGetter)
+			restoreCmd.SetArgs([]string{trName, "--dry-run", "--to-namespace", "debug", "-o", "yaml"})
+			restoreCmd.SetOut(out)
+
+			Expect(restoreCmd.Execute()).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("namespace: debug"))
+		})
 	})
 
 	Context("when running prune command via CLI", func() {
-		var oldResource, newResource *moxv1alpha1.TrashedResource
+		var oldResource, newResource *moxv1alpha2.TrashedResource
 		const ns = "default"
 
 		BeforeEach(func() {
-			oldResource = &moxv1alpha1.TrashedResource{
+			oldResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "old-resource",
 					Namespace:         ns,
 					CreationTimestamp: metav1.Time{Time: time.Now().Add(-2 * time.Hour)}},
 			}
-			newResource = &moxv1alpha1.TrashedResource{
+			newResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:              "new-resource",
 					Namespace:         ns,
@@ -405,7 +890,7 @@ data:
 			mockClientGetter := func(flags *genericclioptions.ConfigFlags) (client.Client, error) { return k8sClient, nil }
 
 			pruneCmd := pruneCmd(configFlags, mockClientGetter)
-			pruneCmd.SetArgs([]string{"old-resource"})
+			pruneCmd.SetArgs([]string{"old-resource", "--yes"})
 			Expect(pruneCmd.Execute()).To(Succeed())
 
 			// Verify old resource is deleted
@@ -421,7 +906,7 @@ data:
 			mockClientGetter := func(flags *genericclioptions.ConfigFlags) (client.Client, error) { return k8sClient, nil }
 
 			pruneCmd := pruneCmd(configFlags, mockClientGetter)
-			pruneCmd.SetArgs([]string{"--older-than", "1h"})
+			pruneCmd.SetArgs([]string{"--older-than", "1h", "--yes"})
 			Expect(pruneCmd.Execute()).To(Succeed())
 
 			// Verify old resource is deleted
@@ -441,16 +926,16 @@ data:
 
 			err := pruneCmd.Execute()
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("either a resource name or the --older-than flag is required"))
+			Expect(err.Error()).To(ContainSubstring("either a resource name or one of --older-than"))
 		})
 	})
 
 	Context("when running restore command via CLI", func() {
-		var newResource *moxv1alpha1.TrashedResource
+		var newResource *moxv1alpha2.TrashedResource
 		const ns = "default"
 
 		BeforeEach(func() {
-			newResource = &moxv1alpha1.TrashedResource{
+			newResource = &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "new-resource",
 					Namespace: ns,
@@ -458,7 +943,7 @@ data:
 						Time: time.Now().Add(-10 * time.Minute),
 					},
 				},
-				Spec: moxv1alpha1.TrashedResourceSpec{
+				Spec: moxv1alpha2.TrashedResourceSpec{
 					Data: `
 apiVersion: v1
 kind: Pod
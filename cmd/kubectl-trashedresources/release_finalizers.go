@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+	utils "trashed-resources/internal/utils"
+)
+
+// releaseFinalizersOptions selects the objects whose trash protection finalizer is removed.
+type releaseFinalizersOptions struct {
+	allNamespaces bool
+	out           io.Writer
+}
+
+func (o releaseFinalizersOptions) writer() io.Writer {
+	if o.out == nil {
+		return os.Stdout
+	}
+	return o.out
+}
+
+func releaseFinalizersCmd(kubernetesConfigFlags *genericclioptions.ConfigFlags, clientGetter clientGetterFunc) *cobra.Command {
+	opts := releaseFinalizersOptions{}
+
+	releaseFinalizersCmd := &cobra.Command{
+		Use:   "release-finalizers KIND...",
+		Short: "Removes the " + moxv1alpha2.TrashProtectionFinalizer + " finalizer, without capturing the objects",
+		Long: `Run it for the finalizerKinds before uninstalling the controller, otherwise their objects can not be deleted.
+Example: kubectl trashedresources release-finalizers deployment secret --all-namespaces`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			ns, _, err := kubernetesConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+			if opts.allNamespaces {
+				ns = ""
+			}
+			k8sClient, err := clientGetter(kubernetesConfigFlags)
+			if err != nil {
+				return err
+			}
+			return releaseFinalizers(k8sClient, args, ns, opts)
+		},
+	}
+
+	releaseFinalizersCmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false,
+		"Release the objects of all namespaces")
+
+	return releaseFinalizersCmd
+}
+
+// releaseFinalizers removes the trash protection finalizer from the objects of the kinds in the namespace, or
+// in all namespaces when it is empty.
+func releaseFinalizers(c client.Client, kinds []string, namespace string, opts releaseFinalizersOptions) error {
+	ctx := context.Background()
+	out := opts.writer()
+
+	for _, kind := range kinds {
+		gvk, err := utils.ResolveKindToWatch(c.RESTMapper(), kind)
+		if err != nil {
+			return err
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list %s: %v", gvk.Kind, err)
+		}
+
+		released := 0
+		for _, object := range list.Items {
+			if !controllerutil.ContainsFinalizer(&object, moxv1alpha2.TrashProtectionFinalizer) {
+				continue
+			}
+			if err := releaseObject(ctx, c, &object); err != nil {
+				return fmt.Errorf("failed to release %s %s: %v", gvk.Kind, objectKey(&object), err)
+			}
+			released++
+		}
+		_, _ = fmt.Fprintf(out, "Released %d %s\n", released, gvk.Kind)
+	}
+	return nil
+}
+
+// releaseObject removes the finalizer, reading the object again when it changed meanwhile.
+func releaseObject(ctx context.Context, c client.Client, object *unstructured.Unstructured) error {
+	return client.IgnoreNotFound(retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := trashedresources.ReleaseTrashProtection(ctx, c, object, false)
+		if apierrors.IsConflict(err) {
+			if getErr := c.Get(ctx, client.ObjectKeyFromObject(object), object); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	}))
+}
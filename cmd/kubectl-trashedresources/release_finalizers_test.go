@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("kubectl-trashedresources release-finalizers", func() {
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+
+	finalizersOf := func(name, namespace string) []string {
+		deployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, deployment)).To(Succeed())
+		return deployment.Finalizers
+	}
+
+	BeforeEach(func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+		protected := []string{"example.com/other", moxv1alpha2.TrashProtectionFinalizer}
+		k8sClient = newTestClientBuilder().
+			WithRESTMapper(mapper).
+			WithObjects(
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a", Finalizers: protected}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-b", Finalizers: protected}},
+			).
+			Build()
+		out = &bytes.Buffer{}
+	})
+
+	It("should remove only the trash protection finalizer of the namespace", func() {
+		Expect(releaseFinalizers(k8sClient, []string{"deployment"}, "team-a",
+			releaseFinalizersOptions{out: out})).To(Succeed())
+
+		Expect(out.String()).To(Equal("Released 1 Deployment\n"))
+		Expect(finalizersOf("api", "team-a")).To(Equal([]string{"example.com/other"}))
+		Expect(finalizersOf("api", "team-b")).To(ContainElement(moxv1alpha2.TrashProtectionFinalizer))
+	})
+
+	It("should release the objects of all namespaces", func() {
+		Expect(releaseFinalizers(k8sClient, []string{"Deployment.apps/v1"}, "",
+			releaseFinalizersOptions{out: out})).To(Succeed())
+
+		Expect(out.String()).To(Equal("Released 2 Deployment\n"))
+		Expect(finalizersOf("api", "team-a")).NotTo(ContainElement(moxv1alpha2.TrashProtectionFinalizer))
+		Expect(finalizersOf("api", "team-b")).NotTo(ContainElement(moxv1alpha2.TrashProtectionFinalizer))
+	})
+
+	It("should fail for kinds unknown to the cluster", func() {
+		Expect(releaseFinalizers(k8sClient, []string{"Unknown"}, "", releaseFinalizersOptions{out: out})).
+			NotTo(Succeed())
+	})
+})
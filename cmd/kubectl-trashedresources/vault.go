@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/controller"
+	"trashed-resources/internal/utils"
+)
+
+// withVaultNamespace returns a clientGetter that also loads the vaultNamespace of the controller ConfigMap
+// when --vault-namespace is not set, so the commands look for the TrashedResources where they are captured.
+func withVaultNamespace(clientGetter clientGetterFunc, vaultNamespaceSet func() bool) clientGetterFunc {
+	return func(flags *genericclioptions.ConfigFlags) (client.Client, error) {
+		c, err := clientGetter(flags)
+		if err != nil || vaultNamespaceSet() {
+			return c, err
+		}
+		return c, loadVaultNamespace(context.Background(), c)
+	}
+}
+
+// loadVaultNamespace sets vaultNamespace from the controller ConfigMap. Without the ConfigMap the controller
+// runs with the default configuration, which has no vault.
+func loadVaultNamespace(ctx context.Context, c client.Reader) error {
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: utils.ControllerNamespace, Name: controller.ConfigMapName}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read the vaultNamespace of the controller ConfigMap %s, "+
+			"set it with --vault-namespace (empty when there is no vault): %w", key, err)
+	}
+	vaultNamespace = utils.GetVaultNamespaceFromConfigMap(*configMap)
+	return nil
+}
+
+// trashNamespace returns the namespace of the TrashedResources of the objects of namespace, the
+// --vault-namespace when it is set.
+func trashNamespace(namespace string) string {
+	if vaultNamespace != "" {
+		return vaultNamespace
+	}
+	return namespace
+}
+
+// trashListOptions selects the TrashedResources of the objects of namespace, or of every namespace when it is
+// empty. In the vault namespace they are selected by the original namespace label.
+func trashListOptions(namespace string) []client.ListOption {
+	switch {
+	case namespace == "" && vaultNamespace == "":
+		return nil
+	case namespace == "":
+		return []client.ListOption{client.InNamespace(vaultNamespace)}
+	case vaultNamespace == "":
+		return []client.ListOption{client.InNamespace(namespace)}
+	}
+	return []client.ListOption{client.InNamespace(vaultNamespace),
+		client.MatchingLabels{moxv1alpha2.OriginalNamespaceLabel: namespace}}
+}
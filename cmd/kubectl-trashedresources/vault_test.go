@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/controller"
+	"trashed-resources/internal/domain/trashedresources"
+	"trashed-resources/internal/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("kubectl-trashedresources with a vault namespace", func() {
+	const vault = "trash-vault"
+	var k8sClient client.Client
+	var out *bytes.Buffer
+	ctx := context.Background()
+
+	newVaultTrashed := func(name, namespace, configMap string, age time.Duration) *moxv1alpha2.TrashedResource {
+		trashed := newTrashedFixture(name, vault, moxv1alpha2.ActionDelete, age,
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+configMap+"\n  namespace: "+namespace+
+				"\ndata:\n  key: value\n")
+		trashed.Annotations = map[string]string{"OriginalName": configMap}
+		trashed.Labels[moxv1alpha2.OriginalNamespaceLabel] = namespace
+		trashed.Labels[moxv1alpha2.OriginalHashLabel] = trashedresources.OriginalHash("ConfigMap", namespace, configMap)
+		return trashed
+	}
+
+	BeforeEach(func() {
+		k8sClient = newTestClientBuilder().Build()
+		out = &bytes.Buffer{}
+
+		for _, trashed := range []*moxv1alpha2.TrashedResource{
+			newVaultTrashed("trashed-deleted-configmap-team-a-app", "team-a", "app", time.Hour),
+			newVaultTrashed("trashed-deleted-configmap-team-b-app", "team-b", "app", time.Hour),
+		} {
+			Expect(k8sClient.Create(ctx, trashed)).To(Succeed())
+		}
+		vaultNamespace = vault
+		DeferCleanup(func() { vaultNamespace = "" })
+	})
+
+	It("should list the TrashedResources of the original namespace", func() {
+		Expect(listResources(k8sClient, "team-a", listOptions{output: outputJSON, out: out})).To(Succeed())
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(json.Unmarshal(out.Bytes(), list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Name).To(Equal("trashed-deleted-configmap-team-a-app"))
+	})
+
+	It("should print the original namespace of all namespaces", func() {
+		Expect(listResources(k8sClient, "team-a", listOptions{allNamespaces: true, output: outputTable, out: out})).
+			To(Succeed())
+		Expect(out.String()).To(MatchRegexp(`^ORIGINAL NAMESPACE\s+NAME`))
+		Expect(out.String()).To(MatchRegexp(`team-a\s+trashed-deleted-configmap-team-a-app`))
+		Expect(out.String()).To(MatchRegexp(`team-b\s+trashed-deleted-configmap-team-b-app`))
+	})
+
+	It("should list the history of the object from the vault", func() {
+		Expect(historyOf(k8sClient, "configmap", "app", "team-b", historyOptions{mode: diffModeUnified, out: out})).
+			To(Succeed())
+		Expect(out.String()).To(ContainSubstring("trashed-deleted-configmap-team-b-app"))
+		Expect(out.String()).NotTo(ContainSubstring("team-a"))
+	})
+
+	It("should restore the object in its original namespace", func() {
+		Expect(restoreResource(k8sClient, "trashed-deleted-configmap-team-b-app", trashNamespace("default"),
+			restoreOptions{out: GinkgoWriter})).To(Succeed())
+
+		restored := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: "team-b"}, restored)).To(Succeed())
+		Expect(restored.Data).To(HaveKeyWithValue("key", "value"))
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: "trashed-deleted-configmap-team-b-app", Namespace: vault},
+			&moxv1alpha2.TrashedResource{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should restore everything captured from a namespace", func() {
+		Expect(bulkRestore(k8sClient, "team-a", bulkRestoreOptions{allFromNamespace: "team-a"},
+			restoreOptions{out: out})).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: "team-a"}, &corev1.ConfigMap{})).
+			To(Succeed())
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: "team-b"}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("kubectl-trashedresources without --vault-namespace", func() {
+	controllerConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: controller.ConfigMapName, Namespace: utils.ControllerNamespace},
+			Data:       data,
+		}
+	}
+	getVaultClient := func(k8sClient client.Client, vaultNamespaceSet bool) (client.Client, error) {
+		clientGetter := withVaultNamespace(func(*genericclioptions.ConfigFlags) (client.Client, error) {
+			return k8sClient, nil
+		}, func() bool { return vaultNamespaceSet })
+		return clientGetter(genericclioptions.NewConfigFlags(false))
+	}
+
+	BeforeEach(func() {
+		vaultNamespace = ""
+		DeferCleanup(func() { vaultNamespace = "" })
+	})
+
+	It("should read the vaultNamespace of the controller ConfigMap", func() {
+		k8sClient := newTestClientBuilder().
+			WithObjects(controllerConfigMap(map[string]string{"vaultNamespace": " trash-vault "})).Build()
+		_, err := getVaultClient(k8sClient, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vaultNamespace).To(Equal("trash-vault"))
+		Expect(trashNamespace("team-a")).To(Equal("trash-vault"))
+	})
+
+	It("should keep the --vault-namespace when it is set", func() {
+		k8sClient := newTestClientBuilder().
+			WithObjects(controllerConfigMap(map[string]string{"vaultNamespace": "trash-vault"})).Build()
+		_, err := getVaultClient(k8sClient, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vaultNamespace).To(BeEmpty())
+	})
+
+	It("should use the namespaces of the objects without the controller ConfigMap", func() {
+		_, err := getVaultClient(newTestClientBuilder().Build(), false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trashNamespace("team-a")).To(Equal("team-a"))
+	})
+
+	It("should fail when the controller ConfigMap can not be read", func() {
+		k8sClient := newTestClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+				opts ...client.GetOption) error {
+				return errors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, key.Name, nil)
+			},
+		}).Build()
+		_, err := getVaultClient(k8sClient, false)
+		Expect(err).To(MatchError(ContainSubstring("set it with --vault-namespace")))
+	})
+})
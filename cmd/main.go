@@ -26,11 +26,15 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -38,7 +42,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
 	"trashed-resources/internal/controller"
+	"trashed-resources/internal/utils"
+	webhookmoxv1alpha2 "trashed-resources/internal/webhook/v1alpha2"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -51,6 +58,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(moxv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(moxv1alpha2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -185,6 +193,21 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "07973d19.mox.app.br",
+		// The controller only reads its own ConfigMap from the cache, the observed kinds have their own
+		// informers.
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.ConfigMap{}: {
+					Namespaces: map[string]cache.Config{utils.ControllerNamespace: {}},
+					Field:      fields.OneTermEqualSelector("metadata.name", controller.ConfigMapName),
+				},
+			},
+		},
+		// The Secrets and the Namespaces are only read once in a while, they are read from the API server
+		// instead of keeping every Secret of the cluster in the cache.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}, &corev1.Namespace{}}},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -202,13 +225,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controller.TrashedResourceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	trashedResourceReconciler := &controller.TrashedResourceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("trashedresources-controller"),
+	}
+	if err = trashedResourceReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TrashedResource")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookmoxv1alpha2.SetupTrashedResourceWebhookWithManager(mgr,
+			trashedResourceReconciler.CurrentConfig); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TrashedResource")
+			os.Exit(1)
+		}
+		trashedResourceReconciler.SetupCaptureWebhookWithManager(mgr)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
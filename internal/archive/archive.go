@@ -0,0 +1,86 @@
+// Package archive keeps the expired TrashedResources before the controller deletes them.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Sink receives every expired TrashedResource before it is deleted. The TrashedResource is deleted only
+// when Archive succeeds.
+type Sink interface {
+	Archive(ctx context.Context, trashed *moxv1alpha2.TrashedResource) error
+}
+
+// Open returns a Sink writing to the storage backend of the URL, see storage.Open, in the format yaml or
+// ndjson.
+func Open(rawURL, format string) (Sink, error) {
+	backend, err := storage.Open(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := NewStorageSink(backend, format)
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// StorageSink writes each TrashedResource to its own file, named with its name and UID, like
+// default/trashed-deleted-configmap-cfg-20260201-230159-5e4b7c1a-0d6f-4f4e-9d5a-2b8c1e0f3a7d.yaml. The
+// ndjson format is one file per TrashedResource too, with a single line, so the files can be concatenated.
+// The key does not change when the archive is retried, the file is overwritten.
+type StorageSink struct {
+	backend storage.Backend
+	format  string
+}
+
+// NewStorageSink returns a StorageSink writing to backend in the format yaml or ndjson.
+func NewStorageSink(backend storage.Backend, format string) (*StorageSink, error) {
+	if format != utils.ArchiveFormatYAML && format != utils.ArchiveFormatNDJSON {
+		return nil, fmt.Errorf("invalid archive format %q, must be %s or %s", format,
+			utils.ArchiveFormatYAML, utils.ArchiveFormatNDJSON)
+	}
+	return &StorageSink{backend: backend, format: format}, nil
+}
+
+// Archive writes the TrashedResource with its apiVersion and kind.
+func (s *StorageSink) Archive(ctx context.Context, trashed *moxv1alpha2.TrashedResource) error {
+	archived := trashed.DeepCopy()
+	archived.APIVersion = moxv1alpha2.GroupVersion.String()
+	archived.Kind = "TrashedResource"
+
+	var data []byte
+	var err error
+	if s.format == utils.ArchiveFormatNDJSON {
+		if data, err = json.Marshal(archived); err == nil {
+			data = append(data, '\n')
+		}
+	} else {
+		data, err = yaml.Marshal(archived)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode TrashedResource %s/%s: %w", trashed.Namespace, trashed.Name, err)
+	}
+
+	if err := s.backend.Put(ctx, archiveKey(trashed, s.format), data); err != nil {
+		return fmt.Errorf("failed to archive TrashedResource %s/%s: %w", trashed.Namespace, trashed.Name, err)
+	}
+	return nil
+}
+
+// archiveKey returns the key of the TrashedResource archived in the format, eg. default/<name>-<uid>.yaml.
+func archiveKey(trashed *moxv1alpha2.TrashedResource, format string) string {
+	name := trashed.Name
+	if trashed.UID != "" {
+		name += "-" + string(trashed.UID)
+	}
+	return fmt.Sprintf("%s/%s.%s", trashed.Namespace, name, format)
+}
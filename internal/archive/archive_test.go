@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func newExpired() *moxv1alpha2.TrashedResource {
+	return &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-configmap-cfg", Namespace: "default", UID: "uid-1"},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			Data:   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n",
+			Action: moxv1alpha2.ActionDelete,
+		},
+	}
+}
+
+func newTestSink(g *WithT, root, format string) *StorageSink {
+	sink, err := NewStorageSink(storage.NewFilesystem(root), format)
+	g.Expect(err).NotTo(HaveOccurred())
+	return sink
+}
+
+func TestStorageSink_YAML(t *testing.T) {
+	g := NewWithT(t)
+	root := t.TempDir()
+
+	g.Expect(newTestSink(g, root, utils.ArchiveFormatYAML).Archive(context.Background(), newExpired())).To(Succeed())
+
+	data, err := os.ReadFile(filepath.Join(root, "default", "trashed-deleted-configmap-cfg-uid-1.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	archived := &moxv1alpha2.TrashedResource{}
+	g.Expect(yaml.Unmarshal(data, archived)).To(Succeed())
+	g.Expect(archived.Kind).To(Equal("TrashedResource"))
+	g.Expect(archived.APIVersion).To(Equal(moxv1alpha2.GroupVersion.String()))
+	g.Expect(archived.Spec).To(Equal(newExpired().Spec))
+}
+
+func TestStorageSink_NDJSON(t *testing.T) {
+	g := NewWithT(t)
+	root := t.TempDir()
+
+	sink := newTestSink(g, root, utils.ArchiveFormatNDJSON)
+	g.Expect(sink.Archive(context.Background(), newExpired())).To(Succeed())
+	// Retrying the archive overwrites the same file
+	g.Expect(sink.Archive(context.Background(), newExpired())).To(Succeed())
+
+	files, err := os.ReadDir(filepath.Join(root, "default"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(files).To(HaveLen(1))
+	data, err := os.ReadFile(filepath.Join(root, "default", "trashed-deleted-configmap-cfg-uid-1.ndjson"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(strings.Count(string(data), "\n")).To(Equal(1))
+	archived := &moxv1alpha2.TrashedResource{}
+	g.Expect(json.Unmarshal(data, archived)).To(Succeed())
+	g.Expect(archived.Name).To(Equal("trashed-deleted-configmap-cfg"))
+}
+
+func TestOpen(t *testing.T) {
+	g := NewWithT(t)
+
+	sink, err := Open("file://"+t.TempDir(), utils.ArchiveFormatYAML)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sink).NotTo(BeNil())
+
+	_, err = Open("file://"+t.TempDir(), "csv")
+	g.Expect(err).To(MatchError(ContainSubstring("invalid archive format")))
+	_, err = Open("ftp://archive", utils.ArchiveFormatYAML)
+	g.Expect(err).To(MatchError(ContainSubstring("invalid storage backend")))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	tr_interactions "trashed-resources/internal/domain/trashedresources"
+	utils "trashed-resources/internal/utils"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// CaptureWebhookPath is where the admission webhook that captures the objects is served.
+const CaptureWebhookPath = "/capture-trashedresources"
+
+// SetupCaptureWebhookWithManager registers the admission webhook that captures the objects as stored before
+// they are deleted or updated. It only captures when captureMode is webhook, the objects are then no longer
+// captured from the informer events.
+func (r *TrashedResourceReconciler) SetupCaptureWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(CaptureWebhookPath, &admission.Webhook{
+		Handler: &captureWebhook{reconciler: r, client: mgr.GetClient()},
+	})
+}
+
+// captureWebhook creates the TrashedResources of the DELETE and UPDATE admission requests, with the user
+// of the request.
+type captureWebhook struct {
+	reconciler *TrashedResourceReconciler
+	client     client.Client
+}
+
+func (w *captureWebhook) Handle(_ context.Context, req admission.Request) admission.Response {
+	config := w.reconciler.CurrentConfig()
+	if config.CaptureMode != utils.CaptureModeWebhook || (req.DryRun != nil && *req.DryRun) {
+		return admission.Allowed("")
+	}
+
+	var action moxv1alpha1.TrashedAction
+	var actionType string
+	switch req.Operation {
+	case admissionv1.Delete:
+		action, actionType = moxv1alpha1.ActionDelete, "deleted"
+	case admissionv1.Update:
+		action, actionType = moxv1alpha1.ActionUpdate, "updated"
+	default:
+		return admission.Allowed("")
+	}
+	if len(req.OldObject.Raw) == 0 {
+		return admission.Allowed("")
+	}
+
+	oldObject := &unstructured.Unstructured{}
+	if err := oldObject.UnmarshalJSON(req.OldObject.Raw); err != nil {
+		return captureFailure(config, fmt.Errorf("failed to decode the object: %w", err))
+	}
+	if action == moxv1alpha1.ActionUpdate {
+		newObject := &unstructured.Unstructured{}
+		if err := newObject.UnmarshalJSON(req.Object.Raw); err != nil {
+			return captureFailure(config, fmt.Errorf("failed to decode the updated object: %w", err))
+		}
+		if !objectChanged(oldObject, newObject) { // Ignore status updates
+			return admission.Allowed("")
+		}
+	}
+
+	policy, capture := w.reconciler.capturePolicy(w.client, oldObject, action)
+	if !capture {
+		return admission.Allowed("")
+	}
+	logger.Info("Admission request detected", "operation", req.Operation, "kind", oldObject.GetKind(),
+		"name", oldObject.GetName(), "namespace", oldObject.GetNamespace(), "user", req.UserInfo.Username)
+	if err := tr_interactions.CaptureManifest(w.client, oldObject, (*tr_interactions.TRReconciler)(w.reconciler),
+		actionType, tr_interactions.CaptureOptions{Policy: policy, RequestedBy: req.UserInfo.Username}); err != nil {
+		return captureFailure(config, err)
+	}
+	return admission.Allowed("")
+}
+
+// objectChanged tells whether the update changed more than the status of the object. The kinds without
+// metadata.generation, eg. ConfigMaps and Secrets, are compared without the status and the metadata updated
+// by the cluster.
+func objectChanged(oldObject, newObject *unstructured.Unstructured) bool {
+	if oldObject.GetGeneration() > 0 {
+		return oldObject.GetGeneration() != newObject.GetGeneration()
+	}
+	return !equality.Semantic.DeepEqual(withoutClusterFields(oldObject), withoutClusterFields(newObject))
+}
+
+func withoutClusterFields(object *unstructured.Unstructured) map[string]any {
+	stripped := object.DeepCopy()
+	unstructured.RemoveNestedField(stripped.Object, "status")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "managedFields")
+	return stripped.Object
+}
+
+// captureFailure denies the request when captureFailurePolicy is Fail, otherwise allows it with a warning.
+func captureFailure(config utils.WatchConfig, err error) admission.Response {
+	if config.CaptureFailurePolicy == utils.CaptureFailureFail {
+		return admission.Denied(fmt.Sprintf("the object could not be captured in a TrashedResource: %v", err))
+	}
+	return admission.Allowed("").WithWarnings(fmt.Sprintf("the object was not captured in a TrashedResource: %v", err))
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Capture admission webhook", func() {
+	var (
+		reconciler *TrashedResourceReconciler
+		fakeClient client.Client
+		webhook    *captureWebhook
+	)
+
+	newDeployment := func(generation int64) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: generation},
+		}
+	}
+	raw := func(obj client.Object) runtime.RawExtension {
+		data, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return runtime.RawExtension{Raw: data}
+	}
+	newRequest := func(operation admissionv1.Operation, oldObj, obj client.Object) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			OldObject: raw(oldObj),
+			UserInfo:  authenticationv1.UserInfo{Username: "jane@example.com"},
+		}}
+		if obj != nil {
+			req.Object = raw(obj)
+		}
+		return req
+	}
+	trashedResources := func() []moxv1alpha2.TrashedResource {
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(fakeClient.List(context.Background(), list)).To(Succeed())
+		return list.Items
+	}
+
+	BeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).Build()
+		reconciler = newConfiguredReconciler(fakeClient, utils.WatchConfig{
+			KindsToWatch:   []string{"Deployment"},
+			ActionsToWatch: []string{"update", "delete"},
+			MinutesToKeep:  "10",
+			HoursToKeep:    "0",
+			DaysToKeep:     "0",
+			CaptureMode:    utils.CaptureModeWebhook,
+		})
+		webhook = &captureWebhook{reconciler: reconciler, client: fakeClient}
+	})
+
+	It("should capture the deleted object with the user of the request", func() {
+		response := webhook.Handle(context.Background(), newRequest(admissionv1.Delete, newDeployment(1), nil))
+		Expect(response.Allowed).To(BeTrue())
+
+		items := trashedResources()
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Spec.Action).To(Equal(moxv1alpha2.ActionDelete))
+		Expect(items[0].Spec.RequestedBy).To(Equal("jane@example.com"))
+		Expect(items[0].Spec.Original.Name).To(Equal("app"))
+	})
+
+	It("should capture the object as stored before an update", func() {
+		response := webhook.Handle(context.Background(),
+			newRequest(admissionv1.Update, newDeployment(1), newDeployment(2)))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(trashedResources()).To(HaveLen(1))
+
+		response = webhook.Handle(context.Background(),
+			newRequest(admissionv1.Update, newDeployment(2), newDeployment(2)))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(trashedResources()).To(HaveLen(1))
+	})
+
+	It("should capture the updates of the kinds without generation, eg. ConfigMaps", func() {
+		configure(reconciler, func(config *utils.WatchConfig) { config.KindsToWatch = []string{"ConfigMap"} })
+		newConfigMap := func(resourceVersion, value string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", ResourceVersion: resourceVersion},
+				Data:       map[string]string{"key": value},
+			}
+		}
+
+		response := webhook.Handle(context.Background(),
+			newRequest(admissionv1.Update, newConfigMap("1", "old"), newConfigMap("2", "new")))
+		Expect(response.Allowed).To(BeTrue())
+		items := trashedResources()
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Spec.Action).To(Equal(moxv1alpha2.ActionUpdate))
+		Expect(items[0].Spec.Data).To(ContainSubstring("key: old"))
+
+		// Only the metadata updated by the cluster changed
+		response = webhook.Handle(context.Background(),
+			newRequest(admissionv1.Update, newConfigMap("2", "new"), newConfigMap("3", "new")))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(trashedResources()).To(HaveLen(1))
+	})
+
+	It("should not capture dry runs, kinds not watched or in the watch mode", func() {
+		dryRun := newRequest(admissionv1.Delete, newDeployment(1), nil)
+		dryRun.DryRun = func(b bool) *bool { return &b }(true)
+		Expect(webhook.Handle(context.Background(), dryRun).Allowed).To(BeTrue())
+
+		configure(reconciler, func(config *utils.WatchConfig) { config.KindsToWatch = []string{"Secret"} })
+		Expect(webhook.Handle(context.Background(), newRequest(admissionv1.Delete, newDeployment(1), nil)).Allowed).
+			To(BeTrue())
+
+		configure(reconciler, func(config *utils.WatchConfig) {
+			config.KindsToWatch = []string{"Deployment"}
+			config.CaptureMode = utils.CaptureModeWatch
+		})
+		Expect(webhook.Handle(context.Background(), newRequest(admissionv1.Delete, newDeployment(1), nil)).Allowed).
+			To(BeTrue())
+		Expect(trashedResources()).To(BeEmpty())
+	})
+
+	It("should leave the captures to the webhook in the webhook mode", func() {
+		Expect(reconciler.HandleDelete(event.DeleteEvent{Object: newDeployment(1)}, fakeClient)).To(BeFalse())
+		Expect(reconciler.HandleUpdate(event.UpdateEvent{ObjectOld: newDeployment(1), ObjectNew: newDeployment(2)},
+			fakeClient)).To(BeFalse())
+		Expect(trashedResources()).To(BeEmpty())
+	})
+
+	It("should allow or deny the requests it fails to capture according to captureFailurePolicy", func() {
+		// Objects larger than maxInlineSize can not be stored without a storageBackend
+		configure(reconciler, func(config *utils.WatchConfig) { config.MaxInlineSize = 16 })
+
+		response := webhook.Handle(context.Background(), newRequest(admissionv1.Delete, newDeployment(1), nil))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(HaveLen(1))
+
+		configure(reconciler, func(config *utils.WatchConfig) {
+			config.CaptureFailurePolicy = utils.CaptureFailureFail
+		})
+		response = webhook.Handle(context.Background(), newRequest(admissionv1.Delete, newDeployment(1), nil))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("could not be captured"))
+		Expect(trashedResources()).To(BeEmpty())
+	})
+})
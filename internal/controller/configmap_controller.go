@@ -0,0 +1,173 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+	utils "trashed-resources/internal/utils"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ConfigMapReconciler reloads the trashed-resources-config ConfigMap into the
+// TrashedResourceReconciler every time it changes.
+type ConfigMapReconciler struct {
+	client.Client
+	Reconciler *TrashedResourceReconciler
+	Watcher    *kindWatcher
+	Protection *TrashProtectionReconciler
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// Reconcile applies the current content of the config ConfigMap, falling back
+// to the default values when it was deleted, and syncs the watched kinds.
+func (r *ConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	configMap := v1.ConfigMap{}
+	if err := r.Get(ctx, req.NamespacedName, &configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		logger.Info("ConfigMap not found, using default values", "name", req.Name, "namespace", req.Namespace)
+		configMap = utils.GetDefaultConfigMap(req.Name)
+	}
+
+	logger.Info("Reloading configMap", "name", req.Name, "namespace", req.Namespace)
+	r.Reconciler.applyConfigMap(configMap)
+	r.Watcher.Sync(ctx)
+	r.Protection.Sync(ctx)
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isConfigMap := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == ConfigMapName && o.GetNamespace() == utils.ControllerNamespace
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1.ConfigMap{}, builder.WithPredicates(isConfigMap)).
+		Named("trashedresources-config").
+		Complete(r)
+}
+
+// kindWatcher starts and stops the dynamic watches of the observed kinds at runtime.
+type kindWatcher struct {
+	mu         sync.Mutex
+	controller controller.Controller
+	cache      cache.Cache
+	mapper     meta.RESTMapper
+	predicates predicate.Predicate
+	watched    map[schema.GroupVersionKind]bool
+	// kinds returns the raw kinds that must be watched.
+	kinds func() []string
+}
+
+func newKindWatcher(c controller.Controller, informers cache.Cache, mapper meta.RESTMapper,
+	predicates predicate.Predicate, watchedKinds []schema.GroupVersionKind, kinds func() []string) *kindWatcher {
+	watched := map[schema.GroupVersionKind]bool{}
+	for _, gvk := range watchedKinds {
+		watched[gvk] = true
+	}
+
+	return &kindWatcher{
+		controller: c,
+		cache:      informers,
+		mapper:     mapper,
+		predicates: predicates,
+		watched:    watched,
+		kinds:      kinds,
+	}
+}
+
+// Sync adds a watch for each kind to watch not yet watched and stops the
+// informers of the watched kinds that are no longer present.
+func (w *kindWatcher) Sync(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	desired := map[schema.GroupVersionKind]bool{}
+	for _, k := range w.kinds() {
+		kind := strings.TrimSpace(k)
+		if kind == "" {
+			continue
+		}
+		gvk, err := utils.ResolveKindToWatch(w.mapper, kind)
+		if err != nil {
+			logger.Error(err, "Unable to resolve kind; ignoring. Use kind.group/version for custom resources", "kind", kind)
+			continue
+		}
+		desired[gvk] = true
+	}
+
+	for gvk := range desired {
+		if w.watched[gvk] {
+			continue
+		}
+		var obj client.Object = newUnstructured(gvk)
+		if err := w.controller.Watch(source.Kind(w.cache, obj, &handler.EnqueueRequestForObject{}, w.predicates)); err != nil {
+			logger.Error(err, "Failed to start watching kind", "gvk", gvk.String())
+			continue
+		}
+		logger.Info("Watching kind", "gvk", gvk.String())
+		w.watched[gvk] = true
+	}
+
+	for gvk := range w.watched {
+		if desired[gvk] {
+			continue
+		}
+		if err := w.cache.RemoveInformer(ctx, newUnstructured(gvk)); err != nil {
+			logger.Error(err, "Failed to stop watching kind", "gvk", gvk.String())
+			continue
+		}
+		logger.Info("Stopped watching kind", "gvk", gvk.String())
+		delete(w.watched, gvk)
+	}
+}
+
+// WatchedKinds returns the kinds currently watched.
+func (w *kindWatcher) WatchedKinds() []schema.GroupVersionKind {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	kinds := make([]schema.GroupVersionKind, 0, len(w.watched))
+	for gvk := range w.watched {
+		kinds = append(kinds, gvk)
+	}
+	return kinds
+}
+
+func newUnstructured(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	return u
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("ConfigMap Controller", func() {
+	Context("When the config ConfigMap changes", func() {
+		ctx := context.Background()
+		configKey := types.NamespacedName{Name: ConfigMapName, Namespace: utils.ControllerNamespace}
+		deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+		secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+		configMapGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+		var (
+			fakeClient   client.Client
+			trReconciler *TrashedResourceReconciler
+			reconciler   *ConfigMapReconciler
+		)
+
+		BeforeEach(func() {
+			fakeClient = fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).Build()
+
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: server.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			trReconciler = &TrashedResourceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+			trController, err := ctrl.NewControllerManagedBy(mgr).
+				For(&moxv1alpha2.TrashedResource{}).
+				Named("trashedresources-reload-test").
+				WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+				Build(trReconciler)
+			Expect(err).NotTo(HaveOccurred())
+
+			reconciler = &ConfigMapReconciler{
+				Client:     fakeClient,
+				Reconciler: trReconciler,
+				Watcher: newKindWatcher(trController, mgr.GetCache(), mgr.GetRESTMapper(), predicate.Funcs{},
+					[]schema.GroupVersionKind{deploymentGVK, configMapGVK},
+					func() []string { return trReconciler.CurrentConfig().AllKindsToWatch() }),
+			}
+		})
+
+		It("should apply the new values and sync the watched kinds", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configKey.Name, Namespace: configKey.Namespace},
+				Data: map[string]string{
+					"kindsToObserve":     "Deployment; Secret",
+					"actionsToObserve":   "delete; update",
+					"namespacesToIgnore": "kube-system",
+					"minutesToKeep":      "5",
+				},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: configKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			config := trReconciler.CurrentConfig()
+			Expect(config.KindsToWatch).To(ConsistOf("Deployment", "Secret"))
+			Expect(config.ActionsToWatch).To(ConsistOf("delete", "update"))
+			Expect(config.MinutesToKeep).To(Equal("5"))
+			Expect(reconciler.Watcher.WatchedKinds()).To(ConsistOf(deploymentGVK, secretGVK))
+		})
+
+		It("should use default values when the ConfigMap is deleted", func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: configKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			config := trReconciler.CurrentConfig()
+			Expect(config.KindsToWatch).To(ConsistOf("Deployment", "Secret", "ConfigMap"))
+			Expect(config.ActionsToWatch).To(ConsistOf("delete"))
+			Expect(reconciler.Watcher.WatchedKinds()).To(ConsistOf(deploymentGVK, secretGVK, configMapGVK))
+		})
+
+		It("should ignore kinds that can not be resolved", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configKey.Name, Namespace: configKey.Namespace},
+				Data:       map[string]string{"kindsToObserve": "Secret; Application.argoproj.io/v1alpha1"},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: configKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.Watcher.WatchedKinds()).To(ConsistOf(secretGVK))
+		})
+	})
+})
@@ -0,0 +1,160 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	tr_interactions "trashed-resources/internal/domain/trashedresources"
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// offlineIndex keeps, in the offlineIndex storage, the last known state of the watched objects that would be
+// captured when deleted. Informer delete events are lost while the controller is not running, so the first
+// time a kind is indexed the objects of the previous index missing from the cluster are captured, from their
+// last known state and with the detected-offline reason. The kinds with data protection are indexed and
+// captured redacted.
+type offlineIndex struct {
+	reconciler *TrashedResourceReconciler
+	client     client.Client
+	mapper     meta.RESTMapper
+	// caughtUp has the kinds whose previous index was already compared with the cluster.
+	caughtUp map[schema.GroupVersionKind]bool
+}
+
+func newOfflineIndex(r *TrashedResourceReconciler, c client.Client, mapper meta.RESTMapper) *offlineIndex {
+	return &offlineIndex{reconciler: r, client: c, mapper: mapper, caughtUp: map[schema.GroupVersionKind]bool{}}
+}
+
+// NeedLeaderElection makes only the leader capture and write the index.
+func (i *offlineIndex) NeedLeaderElection() bool {
+	return true
+}
+
+// Start syncs the index every offlineIndexInterval until ctx is done.
+func (i *offlineIndex) Start(ctx context.Context) error {
+	for {
+		i.sync(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(i.reconciler.CurrentConfig().IndexInterval()):
+		}
+	}
+}
+
+// sync writes the index of every watched kind, catching up the deletions of the kinds not caught up yet.
+// The index of a kind is kept when its deletions could not be caught up, they are retried on the next sync.
+func (i *offlineIndex) sync(ctx context.Context) {
+	config := i.reconciler.CurrentConfig()
+	if config.OfflineIndex == "" {
+		return
+	}
+	backend, err := storage.Open(config.OfflineIndex)
+	if err != nil {
+		logger.Error(err, "Failed to open the offline index", "offlineIndex", config.OfflineIndex)
+		return
+	}
+
+	for _, gvk := range i.kinds(config) {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := i.client.List(ctx, list); err != nil {
+			logger.Error(err, "Failed to list the objects to index", "gvk", gvk.String())
+			continue
+		}
+		for index := range list.Items {
+			list.Items[index].SetGroupVersionKind(gvk)
+		}
+		if !i.caughtUp[gvk] {
+			if !i.catchUp(ctx, backend, gvk, list.Items) {
+				continue
+			}
+			i.caughtUp[gvk] = true
+		}
+
+		indexed := slices.DeleteFunc(list.Items, func(object unstructured.Unstructured) bool {
+			_, capture := i.reconciler.capturePolicy(i.client, &object, moxv1alpha1.ActionDelete)
+			return !capture
+		})
+		if err := tr_interactions.WriteOfflineIndex(ctx, backend, config, gvk, indexed); err != nil {
+			logger.Error(err, "Failed to write the offline index", "gvk", gvk.String())
+		}
+	}
+}
+
+// catchUp captures the objects of the previous index of the kind that are not in the cluster anymore and
+// were not captured yet. It returns false when some of them could not be captured.
+func (i *offlineIndex) catchUp(ctx context.Context, backend storage.Backend, gvk schema.GroupVersionKind,
+	live []unstructured.Unstructured) bool {
+	index, err := tr_interactions.ReadOfflineIndex(ctx, backend, gvk)
+	if err != nil {
+		logger.Error(err, "Failed to read the offline index", "gvk", gvk.String())
+		return false
+	}
+
+	caughtUp := true
+	for _, object := range tr_interactions.MissingObjects(index, live) {
+		policy, capture := i.reconciler.capturePolicy(i.client, object, moxv1alpha1.ActionDelete)
+		if !capture {
+			continue
+		}
+		captured, err := tr_interactions.IsDeletionCaptured(ctx, i.client, object)
+		if err != nil {
+			logger.Error(err, "Failed to find the TrashedResources of the object", "name", object.GetName(),
+				"namespace", object.GetNamespace())
+			caughtUp = false
+			continue
+		}
+		if captured {
+			continue
+		}
+		logger.Info("Deletion detected while offline", "kind", gvk.Kind, "name", object.GetName(),
+			"namespace", object.GetNamespace(), "indexedAt", index.TakenAt.UTC())
+		if err := tr_interactions.CaptureManifest(i.client, object, (*tr_interactions.TRReconciler)(i.reconciler),
+			"deleted", tr_interactions.CaptureOptions{
+				Policy:   policy,
+				Reason:   moxv1alpha2.CaptureReasonDetectedOffline,
+				Redacted: index.Redacted,
+			}); err != nil {
+			caughtUp = false
+		}
+	}
+	return caughtUp
+}
+
+// kinds resolves the kinds of the ConfigMap and of the policies.
+func (i *offlineIndex) kinds(config utils.WatchConfig) []schema.GroupVersionKind {
+	kinds := []schema.GroupVersionKind{}
+	for _, rawKind := range config.AllKindsToWatch() {
+		gvk, err := utils.ResolveKindToWatch(i.mapper, rawKind)
+		if err != nil || slices.Contains(kinds, gvk) {
+			continue
+		}
+		kinds = append(kinds, gvk)
+	}
+	return kinds
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Offline index", func() {
+	var (
+		reconciler *TrashedResourceReconciler
+		fakeClient client.Client
+		mapper     *meta.DefaultRESTMapper
+	)
+
+	newDeployment := func(name, namespace string, uid types.UID) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: uid},
+		}
+	}
+	trashedResources := func() []moxv1alpha2.TrashedResource {
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(fakeClient.List(context.Background(), list)).To(Succeed())
+		return list.Items
+	}
+	// restart syncs a new index, as the controller does when it starts.
+	restart := func() {
+		newOfflineIndex(reconciler, fakeClient, mapper).sync(context.Background())
+	}
+
+	BeforeEach(func() {
+		mapper = meta.NewDefaultRESTMapper(nil)
+		mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithRESTMapper(mapper).
+			WithObjects(
+				newDeployment("api", "default", "1"),
+				newDeployment("worker", "default", "2"),
+				newDeployment("dns", "kube-system", "3"),
+			).
+			Build()
+		reconciler = newConfiguredReconciler(fakeClient, utils.WatchConfig{
+			KindsToWatch:       []string{"Deployment"},
+			ActionsToWatch:     []string{"delete"},
+			NamespacesToIgnore: []string{"kube-system"},
+			MinutesToKeep:      "10",
+			HoursToKeep:        "0",
+			DaysToKeep:         "0",
+			OfflineIndex:       "file://" + GinkgoT().TempDir(),
+		})
+	})
+
+	It("should capture the objects deleted while the controller was offline", func() {
+		restart()
+		Expect(trashedResources()).To(BeEmpty())
+
+		Expect(fakeClient.Delete(context.Background(), newDeployment("worker", "default", "2"))).To(Succeed())
+		Expect(fakeClient.Delete(context.Background(), newDeployment("dns", "kube-system", "3"))).To(Succeed())
+		restart()
+
+		items := trashedResources()
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Spec.Original.Name).To(Equal("worker"))
+		Expect(items[0].Spec.Original.UID).To(Equal(types.UID("2")))
+		Expect(items[0].Spec.Action).To(Equal(moxv1alpha2.ActionDelete))
+		Expect(items[0].Labels).To(HaveKeyWithValue(moxv1alpha2.CaptureReasonLabel,
+			moxv1alpha2.CaptureReasonDetectedOffline))
+		Expect(items[0].Spec.Data).To(ContainSubstring("name: worker"))
+
+		// The deleted object is not in the new index
+		restart()
+		Expect(trashedResources()).To(HaveLen(1))
+	})
+
+	It("should not capture again the deletions captured before the controller stopped", func() {
+		restart()
+		worker := newDeployment("worker", "default", "2")
+		Expect(fakeClient.Delete(context.Background(), worker)).To(Succeed())
+		Expect(reconciler.HandleDelete(event.DeleteEvent{Object: worker}, fakeClient)).To(BeTrue())
+
+		restart()
+		items := trashedResources()
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Labels).NotTo(HaveKey(moxv1alpha2.CaptureReasonLabel))
+	})
+
+	It("should do nothing without offlineIndex", func() {
+		configure(reconciler, func(config *utils.WatchConfig) { config.OfflineIndex = "" })
+		restart()
+		Expect(fakeClient.Delete(context.Background(), newDeployment("worker", "default", "2"))).To(Succeed())
+		configure(reconciler, func(config *utils.WatchConfig) { config.OfflineIndex = "file://" + GinkgoT().TempDir() })
+		restart()
+		Expect(trashedResources()).To(BeEmpty())
+	})
+})
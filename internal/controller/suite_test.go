@@ -33,6 +33,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +63,8 @@ var _ = BeforeSuite(func() {
 	var err error
 	err = moxv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = moxv1alpha2.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
 
@@ -114,3 +118,15 @@ func getFirstFoundEnvTestBinaryDir() string {
 	}
 	return ""
 }
+
+// newConfiguredReconciler returns a reconciler of c with config loaded, as if read from the ConfigMap.
+func newConfiguredReconciler(c client.Client, config utils.WatchConfig) *TrashedResourceReconciler {
+	reconciler := &TrashedResourceReconciler{Client: c, Scheme: k8sClient.Scheme()}
+	configure(reconciler, func(current *utils.WatchConfig) { *current = config })
+	return reconciler
+}
+
+// configure changes the configuration of reconciler while holding its lock, as a ConfigMap reload does.
+func configure(reconciler *TrashedResourceReconciler, update func(config *utils.WatchConfig)) {
+	(*utils.TrashedResourceReconciler)(reconciler).UpdateConfig(update)
+}
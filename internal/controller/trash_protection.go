@@ -0,0 +1,218 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"sync"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	tr_interactions "trashed-resources/internal/domain/trashedresources"
+	utils "trashed-resources/internal/utils"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// protectedObject is the request of the TrashProtectionReconciler, the kind is needed to get the object.
+type protectedObject struct {
+	schema.GroupVersionKind
+	types.NamespacedName
+}
+
+// TrashProtectionReconciler captures the objects of the finalizerKinds before they are deleted: it adds the
+// mox.app.br/trash-protection finalizer to the objects captured on deletion and only removes it once the
+// TrashedResource is created, retrying while the capture fails. The finalizers are removed from the objects
+// of the kinds removed from finalizerKinds.
+type TrashProtectionReconciler struct {
+	client.Client
+	Reconciler *TrashedResourceReconciler
+	Mapper     meta.RESTMapper
+
+	mu         sync.Mutex
+	controller controller.TypedController[protectedObject]
+	cache      cache.Cache
+	watched    map[schema.GroupVersionKind]bool
+}
+
+// Reconcile adds the finalizer to the objects that are captured on deletion, and captures the deleted ones
+// before removing it.
+func (r *TrashProtectionReconciler) Reconcile(ctx context.Context, req protectedObject) (ctrl.Result, error) {
+	object := newUnstructured(req.GroupVersionKind)
+	if err := r.Get(ctx, req.NamespacedName, object); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	object.SetGroupVersionKind(req.GroupVersionKind)
+
+	policy, capture := (*moxv1alpha1.TrashedResourcePolicy)(nil), false
+	if r.Reconciler.CurrentConfig().ProtectsKind(req.GroupVersionKind) {
+		policy, capture = r.Reconciler.capturePolicy(r.Client, object, moxv1alpha1.ActionDelete)
+	}
+	protected := controllerutil.ContainsFinalizer(object, moxv1alpha2.TrashProtectionFinalizer)
+
+	switch {
+	case object.GetDeletionTimestamp() != nil && protected:
+		if capture {
+			if err := r.capture(ctx, object, policy); err != nil {
+				// The finalizer keeps the object until it is captured
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, ignoreGone(tr_interactions.ReleaseTrashProtection(ctx, r.Client, object, capture))
+	case object.GetDeletionTimestamp() == nil && capture && !protected:
+		return ctrl.Result{}, ignoreGone(tr_interactions.AddTrashProtection(ctx, r.Client, object))
+	case object.GetDeletionTimestamp() == nil && !capture && protected:
+		return ctrl.Result{}, ignoreGone(tr_interactions.ReleaseTrashProtection(ctx, r.Client, object, false))
+	}
+	return ctrl.Result{}, nil
+}
+
+// capture creates the TrashedResource of the deleted object, unless it was already captured by the admission
+// webhook or by a previous attempt whose finalizer could not be removed.
+func (r *TrashProtectionReconciler) capture(ctx context.Context, object *unstructured.Unstructured,
+	policy *moxv1alpha1.TrashedResourcePolicy) error {
+	captured, err := tr_interactions.IsDeletionCaptured(ctx, r.Client, object)
+	if err != nil || captured {
+		return err
+	}
+	logger.Info("Deletion of a protected object detected", "kind", object.GetKind(), "name", object.GetName(),
+		"namespace", object.GetNamespace())
+	// The object is captured as it was before the finalizer was added, so it is restored without it
+	unprotected := object.DeepCopy()
+	controllerutil.RemoveFinalizer(unprotected, moxv1alpha2.TrashProtectionFinalizer)
+	return tr_interactions.CaptureManifest(r.Client, unprotected, (*tr_interactions.TRReconciler)(r.Reconciler),
+		"deleted", tr_interactions.CaptureOptions{Policy: policy})
+}
+
+// ignoreGone ignores the errors of the objects deleted meanwhile, the conflicts are retried.
+func ignoreGone(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// SetupWithManager sets up the controller with the Manager and watches the finalizerKinds.
+func (r *TrashProtectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	trashProtection, err := controller.NewTyped("trashprotection", mgr, controller.TypedOptions[protectedObject]{
+		Reconciler: r,
+	})
+	if err != nil {
+		return err
+	}
+	r.controller = trashProtection
+	if r.cache == nil {
+		r.cache = mgr.GetCache()
+	}
+	r.watched = map[schema.GroupVersionKind]bool{}
+	r.Sync(context.Background())
+	return nil
+}
+
+// Sync watches the finalizerKinds not watched yet and removes the finalizer from the objects of the kinds
+// no longer in finalizerKinds.
+func (r *TrashProtectionReconciler) Sync(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config := r.Reconciler.CurrentConfig()
+	desired := map[schema.GroupVersionKind]bool{}
+	for _, rawKind := range config.FinalizerKinds {
+		gvk, err := utils.ResolveKindToWatch(r.Mapper, rawKind)
+		if err != nil {
+			logger.Error(err, "Unable to resolve finalizer kind; ignoring", "kind", rawKind)
+			continue
+		}
+		// Only the observed kinds are captured, their informers are shared with the kindWatcher.
+		if !slices.ContainsFunc(config.AllKindsToWatch(), func(rawKind string) bool {
+			return utils.MatchesKindToWatch(rawKind, gvk)
+		}) {
+			logger.Info("Finalizer kind is not observed; ignoring", "kind", rawKind)
+			continue
+		}
+		desired[gvk] = true
+	}
+
+	for gvk := range desired {
+		if r.watched[gvk] || r.controller == nil {
+			continue
+		}
+		if err := r.controller.Watch(r.source(gvk)); err != nil {
+			logger.Error(err, "Failed to start watching finalizer kind", "gvk", gvk.String())
+			continue
+		}
+		logger.Info("Protecting kind with finalizer", "gvk", gvk.String())
+		r.watched[gvk] = true
+	}
+
+	for gvk := range r.watched {
+		if desired[gvk] {
+			continue
+		}
+		if err := r.releaseKind(ctx, gvk); err != nil {
+			logger.Error(err, "Failed to remove the finalizers of kind", "gvk", gvk.String())
+			continue
+		}
+		logger.Info("Stopped protecting kind with finalizer", "gvk", gvk.String())
+		delete(r.watched, gvk)
+	}
+}
+
+// source enqueues the objects of the kind with the finalizer to remove or to add. The protected objects that
+// are not being deleted need nothing.
+func (r *TrashProtectionReconciler) source(gvk schema.GroupVersionKind) source.TypedSource[protectedObject] {
+	toProtectedObject := handler.TypedEnqueueRequestsFromMapFunc(
+		func(_ context.Context, object client.Object) []protectedObject {
+			return []protectedObject{{GroupVersionKind: gvk, NamespacedName: client.ObjectKeyFromObject(object)}}
+		})
+	needsReconcile := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetDeletionTimestamp() != nil ||
+			!controllerutil.ContainsFinalizer(object, moxv1alpha2.TrashProtectionFinalizer)
+	})
+	return source.TypedKind[client.Object, protectedObject](r.cache, newUnstructured(gvk), toProtectedObject,
+		needsReconcile)
+}
+
+// releaseKind removes the finalizer from every object of the kind.
+func (r *TrashProtectionReconciler) releaseKind(ctx context.Context, gvk schema.GroupVersionKind) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.List(ctx, list); err != nil {
+		return err
+	}
+	for index := range list.Items {
+		if err := ignoreGone(tr_interactions.ReleaseTrashProtection(ctx, r.Client, &list.Items[index], false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Trash protection", func() {
+	var (
+		reconciler *TrashedResourceReconciler
+		protection *TrashProtectionReconciler
+		fakeClient client.Client
+	)
+
+	deploymentGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+	request := func(name, namespace string) protectedObject {
+		return protectedObject{GroupVersionKind: deploymentGVK,
+			NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	}
+	reconcile := func(name, namespace string) {
+		_, err := protection.Reconcile(context.Background(), request(name, namespace))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	getDeployment := func(name, namespace string) (*appsv1.Deployment, error) {
+		deployment := &appsv1.Deployment{}
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, deployment)
+		return deployment, err
+	}
+	trashedResources := func() []moxv1alpha2.TrashedResource {
+		list := &moxv1alpha2.TrashedResourceList{}
+		Expect(fakeClient.List(context.Background(), list)).To(Succeed())
+		return list.Items
+	}
+
+	BeforeEach(func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(deploymentGVK, meta.RESTScopeNamespace)
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithRESTMapper(mapper).
+			WithObjects(
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "kube-system"}},
+			).
+			Build()
+		reconciler = newConfiguredReconciler(fakeClient, utils.WatchConfig{
+			KindsToWatch:       []string{"Deployment"},
+			ActionsToWatch:     []string{"delete"},
+			NamespacesToIgnore: []string{"kube-system"},
+			FinalizerKinds:     []string{"Deployment.apps"},
+			MinutesToKeep:      "10",
+			HoursToKeep:        "0",
+			DaysToKeep:         "0",
+		})
+		protection = &TrashProtectionReconciler{
+			Client:     fakeClient,
+			Reconciler: reconciler,
+			Mapper:     mapper,
+			watched:    map[schema.GroupVersionKind]bool{},
+		}
+	})
+
+	It("should capture the protected objects before their deletion completes", func() {
+		reconcile("api", "default")
+		reconcile("dns", "kube-system")
+		api, err := getDeployment("api", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.Finalizers).To(ContainElement(moxv1alpha2.TrashProtectionFinalizer))
+		dns, err := getDeployment("dns", "kube-system")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dns.Finalizers).To(BeEmpty())
+
+		Expect(fakeClient.Delete(context.Background(), api)).To(Succeed())
+		api, err = getDeployment("api", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.DeletionTimestamp).NotTo(BeNil())
+
+		reconcile("api", "default")
+		_, err = getDeployment("api", "default")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		items := trashedResources()
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Spec.Original.Name).To(Equal("api"))
+		Expect(items[0].Spec.Action).To(Equal(moxv1alpha2.ActionDelete))
+		Expect(items[0].Spec.Data).NotTo(ContainSubstring(moxv1alpha2.TrashProtectionFinalizer))
+
+		// The delete event of the captured object is ignored
+		api.SetGroupVersionKind(deploymentGVK)
+		api.Finalizers = nil
+		api.Annotations = map[string]string{moxv1alpha2.CapturedAnnotation: "finalizer"}
+		Expect(reconciler.HandleDelete(event.DeleteEvent{Object: api}, fakeClient)).To(BeFalse())
+		Expect(trashedResources()).To(HaveLen(1))
+	})
+
+	It("should remove the finalizer from the kinds no longer protected", func() {
+		reconcile("api", "default")
+		protection.watched[deploymentGVK] = true
+
+		configure(reconciler, func(config *utils.WatchConfig) { config.FinalizerKinds = nil })
+		protection.Sync(context.Background())
+		Expect(protection.watched).To(BeEmpty())
+		api, err := getDeployment("api", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.Finalizers).To(BeEmpty())
+		Expect(api.Annotations).NotTo(HaveKey(moxv1alpha2.CapturedAnnotation))
+
+		// An object deleted after the kind is no longer protected is released without a capture
+		configure(reconciler, func(config *utils.WatchConfig) { config.FinalizerKinds = []string{"Deployment"} })
+		reconcile("api", "default")
+		Expect(fakeClient.Delete(context.Background(), api)).To(Succeed())
+		configure(reconciler, func(config *utils.WatchConfig) { config.FinalizerKinds = nil })
+		reconcile("api", "default")
+		_, err = getDeployment("api", "default")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(trashedResources()).To(BeEmpty())
+	})
+})
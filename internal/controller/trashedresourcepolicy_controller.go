@@ -0,0 +1,113 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	utils "trashed-resources/internal/utils"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// policyConditionReady reports whether every kind of a policy is served by the cluster.
+	policyConditionReady = "Ready"
+)
+
+// TrashedResourcePolicyReconciler merges every TrashedResourcePolicy into the TrashedResourceReconciler
+// and watches the kinds they capture.
+type TrashedResourcePolicyReconciler struct {
+	client.Client
+	Reconciler *TrashedResourceReconciler
+	Watcher    *kindWatcher
+	Protection *TrashProtectionReconciler
+	Mapper     meta.RESTMapper
+}
+
+// +kubebuilder:rbac:groups=mox.app.br,resources=trashedresourcepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=mox.app.br,resources=trashedresourcepolicies/status,verbs=get;update;patch
+// Reconcile reloads all the policies on every change of any of them, since they are
+// evaluated together, and reports on the changed policy whether its kinds could be resolved.
+func (r *TrashedResourcePolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	list := &moxv1alpha1.TrashedResourcePolicyList{}
+	if err := r.List(ctx, list); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	policies := slices.DeleteFunc(list.Items, func(policy moxv1alpha1.TrashedResourcePolicy) bool {
+		return !policy.DeletionTimestamp.IsZero()
+	})
+	slices.SortFunc(policies, func(a, b moxv1alpha1.TrashedResourcePolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	r.Reconciler.applyPolicies(policies)
+	r.Watcher.Sync(ctx)
+	r.Protection.Sync(ctx)
+
+	idx := slices.IndexFunc(policies, func(policy moxv1alpha1.TrashedResourcePolicy) bool {
+		return policy.Name == req.Name
+	})
+	if idx < 0 {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, &policies[idx])
+}
+
+func (r *TrashedResourcePolicyReconciler) updateStatus(ctx context.Context, policy *moxv1alpha1.TrashedResourcePolicy) error {
+	unresolved := []string{}
+	for _, kind := range policy.Spec.Kinds {
+		if _, err := utils.ResolveKindToWatch(r.Mapper, kind.String()); err != nil {
+			unresolved = append(unresolved, kind.String())
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               policyConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "KindsResolved",
+		Message:            "All kinds are watched",
+		ObservedGeneration: policy.Generation,
+	}
+	if len(unresolved) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "UnresolvedKinds"
+		condition.Message = fmt.Sprintf("Kinds not served by the cluster: %s", strings.Join(unresolved, ", "))
+	}
+
+	policy.Status.ObservedGeneration = policy.Generation
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+
+	return r.Status().Update(ctx, policy)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TrashedResourcePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&moxv1alpha1.TrashedResourcePolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("trashedresourcepolicy").
+		Complete(r)
+}
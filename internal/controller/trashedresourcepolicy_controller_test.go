@@ -0,0 +1,143 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("TrashedResourcePolicy Controller", func() {
+	Context("When reconciling policies", func() {
+		ctx := context.Background()
+		trashedResourceGVK := schema.GroupVersionKind{Group: "mox.app.br", Version: "v1alpha1", Kind: "TrashedResource"}
+		secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+
+		var (
+			fakeClient   client.Client
+			trReconciler *TrashedResourceReconciler
+			reconciler   *TrashedResourcePolicyReconciler
+		)
+
+		BeforeEach(func() {
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithStatusSubresource(&moxv1alpha1.TrashedResourcePolicy{}).
+				Build()
+
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: server.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			trReconciler = &TrashedResourceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+			trController, err := ctrl.NewControllerManagedBy(mgr).
+				For(&moxv1alpha2.TrashedResource{}).
+				Named("trashedresources-policy-test").
+				WithOptions(controller.Options{SkipNameValidation: ptr.To(true)}).
+				Build(trReconciler)
+			Expect(err).NotTo(HaveOccurred())
+
+			reconciler = &TrashedResourcePolicyReconciler{
+				Client:     fakeClient,
+				Reconciler: trReconciler,
+				Mapper:     mgr.GetRESTMapper(),
+				Watcher: newKindWatcher(trController, mgr.GetCache(), mgr.GetRESTMapper(), predicate.Funcs{}, nil,
+					func() []string { return trReconciler.CurrentConfig().AllKindsToWatch() }),
+			}
+		})
+
+		It("should merge the policies, watch their kinds and report them as ready", func() {
+			for _, policy := range []*moxv1alpha1.TrashedResourcePolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "secrets"},
+					Spec: moxv1alpha1.TrashedResourcePolicySpec{
+						Kinds:   []moxv1alpha1.PolicyKind{{Kind: "Secret", Version: "v1"}},
+						Actions: []moxv1alpha1.TrashedAction{moxv1alpha1.ActionDelete},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "custom-resources"},
+					Spec: moxv1alpha1.TrashedResourcePolicySpec{
+						Kinds:   []moxv1alpha1.PolicyKind{{Group: "mox.app.br", Kind: "TrashedResource"}},
+						Actions: []moxv1alpha1.TrashedAction{moxv1alpha1.ActionUpdate},
+					},
+				},
+			} {
+				Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "secrets"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			policies := trReconciler.CurrentConfig().Policies
+			Expect(policies).To(HaveLen(2))
+			Expect(policies[0].Name).To(Equal("custom-resources"))
+			Expect(reconciler.Watcher.WatchedKinds()).To(ConsistOf(secretGVK, trashedResourceGVK))
+
+			policy := &moxv1alpha1.TrashedResourcePolicy{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "secrets"}, policy)).To(Succeed())
+			Expect(apimeta.IsStatusConditionTrue(policy.Status.Conditions, policyConditionReady)).To(BeTrue())
+		})
+
+		It("should report the kinds that can not be resolved", func() {
+			Expect(fakeClient.Create(ctx, &moxv1alpha1.TrashedResourcePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "argo"},
+				Spec: moxv1alpha1.TrashedResourcePolicySpec{
+					Kinds:   []moxv1alpha1.PolicyKind{{Group: "argoproj.io", Kind: "Application"}},
+					Actions: []moxv1alpha1.TrashedAction{moxv1alpha1.ActionDelete},
+				},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "argo"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			policy := &moxv1alpha1.TrashedResourcePolicy{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "argo"}, policy)).To(Succeed())
+			condition := apimeta.FindStatusCondition(policy.Status.Conditions, policyConditionReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("Application.argoproj.io"))
+			Expect(reconciler.Watcher.WatchedKinds()).To(BeEmpty())
+		})
+
+		It("should drop deleted policies", func() {
+			trReconciler.applyPolicies([]moxv1alpha1.TrashedResourcePolicy{{ObjectMeta: metav1.ObjectMeta{Name: "gone"}}})
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "gone"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trReconciler.CurrentConfig().Policies).To(BeEmpty())
+		})
+	})
+})
@@ -19,26 +19,34 @@ package controller
 import (
 	"context"
 	"strings"
+	"time"
 	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
 	tr_interactions "trashed-resources/internal/domain/trashedresources"
+	"trashed-resources/internal/metrics"
 	utils "trashed-resources/internal/utils"
 
 	"slices"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var (
 	logger = log.Log
-	cmName = "trashed-resources-config"
+	// ConfigMapName is the name of the controller ConfigMap, in the ControllerNamespace.
+	ConfigMapName = "trashed-resources-config"
 )
 
 // TrashedResourceReconciler wraps the common reconciler to allow defining methods in this package.
@@ -47,6 +55,7 @@ type TrashedResourceReconciler utils.TrashedResourceReconciler
 // +kubebuilder:rbac:groups=mox.app.br,resources=trashedresources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=mox.app.br,resources=trashedresources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=mox.app.br,resources=trashedresources/finalizers,verbs=update
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // the trashedresources object against the actual cluster state, and then
@@ -65,83 +74,269 @@ func (r *TrashedResourceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	if trashedResource == nil {
 		return ctrl.Result{}, nil
 	}
+	// Being deleted: remove the captured object from the storage backend before it goes away
+	if !trashedResource.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, tr_interactions.ReleaseExternalData(ctx, r.Client, r.CurrentConfig(), trashedResource)
+	}
 
-	// 3. Check expiration and delete if expired
-	timeRemaining := utils.GetTimeRemaining(trashedResource.Spec.KeepUntil)
+	// 3. Check expiration and delete if expired. A TrashedResource converted from v1alpha1 with
+	// an invalid keepUntil has no expiry and is kept until it is fixed or deleted.
+	if trashedResource.Spec.KeepUntil.IsZero() {
+		logger.Info("TrashedResource without a valid keepUntil, it will not expire", "name", req.Name, "namespace", req.Namespace)
+		return ctrl.Result{}, r.updateStatus(ctx, trashedResource)
+	}
+	timeRemaining := utils.GetTimeRemaining(trashedResource.Spec.KeepUntil.Time)
 	if timeRemaining <= 0 {
+		// Archive it first when an archive sink is configured, it is kept until the archive succeeds
+		if err := tr_interactions.ArchiveExpired(ctx, r.Client, r.CurrentConfig(), trashedResource); err != nil {
+			logger.Error(err, "Failed to archive the expired TrashedResource, it will not be deleted yet",
+				"name", req.Name, "namespace", req.Namespace)
+			metrics.ArchiveErrors.WithLabelValues(req.Namespace).Inc()
+			return ctrl.Result{}, err
+		}
 		logger.Info("TrashedResource expired, deleting", "name", req.Name, "namespace", req.Namespace)
-		return ctrl.Result{}, tr_interactions.DeleteToReconcile(ctx, r.Client, req.Name, req.Namespace)
+		if err := tr_interactions.DeleteToReconcile(ctx, r.Client, req.Name, req.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+		tr_interactions.RecordEvent(r.Recorder, trashedResource, nil, v1.EventTypeNormal, tr_interactions.EventReasonExpired,
+			"Expire", "Retention ended at %s, the TrashedResource was deleted",
+			trashedResource.Spec.KeepUntil.UTC().Format(time.RFC3339))
+		metrics.Expired.WithLabelValues(req.Namespace).Inc()
+		// The storage backend data is released on the next reconcile, before the finalizer is removed
+		if controllerutil.ContainsFinalizer(trashedResource, moxv1alpha2.ExternalDataFinalizer) {
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// 4. Update the status with the phase, the conditions and the original object
+	if err := r.updateStatus(ctx, trashedResource); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// 3. Requeue after the remaining time
+	// 5. Requeue when it starts expiring or after the remaining time
+	_, untilExpiring := tr_interactions.BuildStatus(trashedResource, utils.Now().Time)
+	if untilExpiring > 0 && untilExpiring < timeRemaining {
+		return ctrl.Result{RequeueAfter: untilExpiring}, nil
+	}
 	return ctrl.Result{RequeueAfter: timeRemaining}, nil
 }
 
+// updateStatus updates the status of the TrashedResource when it changed.
+func (r *TrashedResourceReconciler) updateStatus(ctx context.Context, trashedResource *moxv1alpha2.TrashedResource) error {
+	status, _ := tr_interactions.BuildStatus(trashedResource, utils.Now().Time)
+	if equality.Semantic.DeepEqual(status, trashedResource.Status) {
+		return nil
+	}
+	trashedResource.Status = status
+	return r.Status().Update(ctx, trashedResource)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *TrashedResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.Config = utils.GetAllConfigsFromConfigMap(mgr, cmName)
-	r.KindsToWatch = utils.GetKindsToWatchFromConfigMap(r.Config)
-	r.ActionsToWatch = utils.GetActionsToWatchFromConfigMap(r.Config)
-	r.NamespacesToIgnore = utils.GetNamespacesToIgnoreFromConfigMap(r.Config)
-	r.MinutesToKeep = utils.GetMinutesToKeepFromConfigMap(r.Config)
-	r.HoursToKeep = utils.GetHoursToKeepFromConfigMap(r.Config)
-	r.DaysToKeep = utils.GetDaysToKeepFromConfigMap(r.Config)
-
-	logger.Info("# Kinds found to watch ", "kinds", r.KindsToWatch)
-	logger.Info("# Actions found to watch ", "actions", r.ActionsToWatch)
-	logger.Info("# Namespaces to ignore ", "namespaces", r.NamespacesToIgnore)
-	logger.Info("# Minutes to keep ", "minutes", r.MinutesToKeep)
-	logger.Info("# Hours to keep ", "hours", r.HoursToKeep)
-	logger.Info("# Days to keep ", "days", r.DaysToKeep)
+	r.applyConfigMap(utils.GetAllConfigsFromConfigMap(mgr, ConfigMapName))
+
+	// The manager cache only holds the controller ConfigMap, the observed kinds have their own informers.
+	observed, err := cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(observed); err != nil {
+		return err
+	}
 
+	// The capture filter only applies to the watched kinds: every event of the TrashedResources must be
+	// reconciled, e.g. the deletion of the ones whose finalizer must be removed.
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&moxv1alpha1.TrashedResource{}).
-		Owns(&moxv1alpha1.TrashedResource{}).
-		WithEventFilter(predicate.Funcs{
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				return r.HandleUpdate(e, mgr.GetClient())
-			},
-			DeleteFunc: func(e event.DeleteEvent) bool {
-				return r.HandleDelete(e, mgr.GetClient())
-			},
-		}).
+		For(&moxv1alpha2.TrashedResource{}).
+		Owns(&moxv1alpha2.TrashedResource{}).
 		Named("trashedresources")
 
 	// For each Kind present in config, add a Watch.
-	appendKindsToWatch(builder, r.Config)
+	watchedKinds := appendKindsToWatch(builder, observed, mgr.GetRESTMapper(), r.CurrentConfig().KindsToWatch,
+		r.eventFilter(mgr.GetClient()))
 
-	return builder.Complete(r)
+	trController, err := builder.Build(r)
+	if err != nil {
+		return err
+	}
+	watcher := newKindWatcher(trController, observed, mgr.GetRESTMapper(), r.eventFilter(mgr.GetClient()),
+		watchedKinds, func() []string { return r.CurrentConfig().AllKindsToWatch() })
+
+	// trashedresources_active is counted from the cache on every scrape of the metrics endpoint.
+	if err := metrics.RegisterActive(mgr.GetClient()); err != nil {
+		return err
+	}
+
+	// Capture the objects deleted while the controller was not running.
+	if err := mgr.Add(newOfflineIndex(r, mgr.GetClient(), mgr.GetRESTMapper())); err != nil {
+		return err
+	}
+
+	// Keep the objects of the finalizerKinds until they are captured.
+	protection := &TrashProtectionReconciler{
+		Client:     mgr.GetClient(),
+		Reconciler: r,
+		Mapper:     mgr.GetRESTMapper(),
+		cache:      observed,
+	}
+	if err := protection.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	// Keep watching the ConfigMap to apply its changes without restarting the controller.
+	if err := (&ConfigMapReconciler{
+		Client:     mgr.GetClient(),
+		Reconciler: r,
+		Watcher:    watcher,
+		Protection: protection,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	return (&TrashedResourcePolicyReconciler{
+		Client:     mgr.GetClient(),
+		Reconciler: r,
+		Watcher:    watcher,
+		Protection: protection,
+		Mapper:     mgr.GetRESTMapper(),
+	}).SetupWithManager(mgr)
 }
 
-func (r *TrashedResourceReconciler) HandleUpdate(e event.UpdateEvent, c client.Client) bool {
-	keyExists := slices.Contains(r.ActionsToWatch, "update")
-	ignoreNamespace := slices.Contains(r.NamespacesToIgnore, e.ObjectOld.GetNamespace()) ||
-		slices.Contains(r.NamespacesToIgnore, e.ObjectNew.GetNamespace())
+// applyConfigMap loads the ConfigMap values into the reconciler and logs them.
+func (r *TrashedResourceReconciler) applyConfigMap(configMapData v1.ConfigMap) {
+	(*utils.TrashedResourceReconciler)(r).ApplyConfigMap(configMapData)
+	config := r.CurrentConfig()
+
+	logger.Info("# Kinds found to watch ", "kinds", config.KindsToWatch)
+	logger.Info("# Actions found to watch ", "actions", config.ActionsToWatch)
+	logger.Info("# Namespaces to ignore ", "namespaces", config.NamespacesToIgnore)
+	logger.Info("# Minutes to keep ", "minutes", config.MinutesToKeep)
+	logger.Info("# Hours to keep ", "hours", config.HoursToKeep)
+	logger.Info("# Days to keep ", "days", config.DaysToKeep)
+	logger.Info("# Retention by kind ", "retentionByKind", config.RetentionByKind)
+	logger.Info("# Retention by namespace ", "retentionByNamespace", config.RetentionByNamespace)
+	logger.Info("# Data protection by kind ", "dataProtectionByKind", config.DataProtectionByKind)
+	logger.Info("# Data storage ", "compressAbove", config.CompressionThreshold(),
+		"maxInlineSize", config.InlineLimit(), "storageBackend", config.StorageBackend)
+	logger.Info("# Archive ", "archiveSink", config.ArchiveSink, "archiveFormat", config.ArchiveFormat)
+	logger.Info("# Capture ", "captureMode", config.CaptureMode, "captureFailurePolicy", config.CaptureFailurePolicy)
+	logger.Info("# Offline index ", "offlineIndex", config.OfflineIndex, "offlineIndexInterval", config.IndexInterval())
+	logger.Info("# Vault namespace ", "vaultNamespace", config.VaultNamespace)
+}
+
+// applyPolicies replaces the TrashedResourcePolicies used to capture objects.
+func (r *TrashedResourceReconciler) applyPolicies(policies []moxv1alpha1.TrashedResourcePolicy) {
+	(*utils.TrashedResourceReconciler)(r).ApplyPolicies(policies)
+	logger.Info("# Policies loaded ", "count", len(policies))
+}
 
+// CurrentConfig returns a copy of the configuration loaded from the ConfigMap.
+func (r *TrashedResourceReconciler) CurrentConfig() utils.WatchConfig {
+	return (*utils.TrashedResourceReconciler)(r).CurrentConfig()
+}
+
+// eventFilter captures the updated and deleted objects of the watched kinds.
+func (r *TrashedResourceReconciler) eventFilter(c client.Client) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return r.HandleUpdate(e, c)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return r.HandleDelete(e, c)
+		},
+	}
+}
+
+func (r *TrashedResourceReconciler) HandleUpdate(e event.UpdateEvent, c client.Client) bool {
+	if r.CurrentConfig().CaptureMode == utils.CaptureModeWebhook { // Captured by the admission webhook
+		return false
+	}
 	if e.ObjectOld.GetObjectKind().GroupVersionKind().Kind == "" ||
-		e.ObjectOld.GetGeneration() == e.ObjectNew.GetGeneration() || // Ignore status updates
-		!keyExists || ignoreNamespace {
+		e.ObjectOld.GetGeneration() == e.ObjectNew.GetGeneration() { // Ignore status updates
+		return false
+	}
+	if e.ObjectNew.GetDeletionTimestamp() != nil &&
+		controllerutil.ContainsFinalizer(e.ObjectNew, moxv1alpha2.TrashProtectionFinalizer) {
+		return false // Captured by the TrashProtectionReconciler, the deletion also bumps the generation
+	}
+	policy, capture := r.capturePolicy(c, e.ObjectOld, moxv1alpha1.ActionUpdate)
+	if !capture {
 		return false
 	}
 	logger.Info("Update event detected", "name", e.ObjectOld.GetName(), "namespace", e.ObjectOld.GetNamespace())
-	tr_interactions.CreateOrUpdatedManifest(c, e.ObjectOld, (*tr_interactions.TRReconciler)(r), "updated")
+	tr_interactions.CreateOrUpdatedManifest(c, e.ObjectOld, (*tr_interactions.TRReconciler)(r), "updated", policy)
 	return true
 }
 
 func (r *TrashedResourceReconciler) HandleDelete(e event.DeleteEvent, c client.Client) bool {
-	keyExists := slices.Contains(r.ActionsToWatch, "delete")
-	ignoreNamespace := slices.Contains(r.NamespacesToIgnore, e.Object.GetNamespace())
-
-	if e.Object.GetObjectKind().GroupVersionKind().Kind == "" || (!keyExists || ignoreNamespace) {
+	if r.CurrentConfig().CaptureMode == utils.CaptureModeWebhook { // Captured by the admission webhook
+		return false
+	}
+	if e.Object.GetObjectKind().GroupVersionKind().Kind == "" ||
+		e.Object.GetAnnotations()[moxv1alpha2.CapturedAnnotation] != "" { // Captured before the deletion completed
+		return false
+	}
+	policy, capture := r.capturePolicy(c, e.Object, moxv1alpha1.ActionDelete)
+	if !capture {
 		return false
 	}
 	logger.Info("Delete event detected", "name", e.Object.GetName(), "namespace", e.Object.GetNamespace())
-	tr_interactions.CreateOrUpdatedManifest(c, e.Object, (*tr_interactions.TRReconciler)(r), "deleted")
+	tr_interactions.CreateOrUpdatedManifest(c, e.Object, (*tr_interactions.TRReconciler)(r), "deleted", policy)
 	return true
 }
 
-func appendKindsToWatch(builder *ctrl.Builder, configMapData v1.ConfigMap) {
-	rawKinds := utils.GetKindsToWatchFromConfigMap(configMapData)
+// capturePolicy decides whether the object must be captured for the action. The TrashedResourcePolicies
+// are evaluated first and the ConfigMap rules apply to the objects not captured by any policy. The Secrets
+// holding the data of TrashedResources are never captured.
+func (r *TrashedResourceReconciler) capturePolicy(c client.Client, kubernetesObject client.Object,
+	action moxv1alpha1.TrashedAction) (*moxv1alpha1.TrashedResourcePolicy, bool) {
+	if tr_interactions.IsDataSecret(kubernetesObject) {
+		return nil, false
+	}
+	config := r.CurrentConfig()
+
+	if len(config.Policies) > 0 {
+		namespaceLabels := map[string]string{}
+		if kubernetesObject.GetNamespace() != "" && tr_interactions.PoliciesUseNamespaceSelector(config.Policies) {
+			namespace := &v1.Namespace{}
+			err := c.Get(context.Background(), client.ObjectKey{Name: kubernetesObject.GetNamespace()}, namespace)
+			if err != nil {
+				logger.Error(err, "Failed to get namespace labels", "namespace", kubernetesObject.GetNamespace())
+			}
+			namespaceLabels = namespace.Labels
+		}
+		policy := tr_interactions.MatchPolicy(config.Policies, kubernetesObject, action, namespaceLabels,
+			utils.GetDurationToKeepFromConfig(config))
+		if policy != nil {
+			return policy, true
+		}
+	}
+
+	return nil, configMapCaptures(config, kubernetesObject, action)
+}
+
+// configMapCaptures verifies the object against the actionsToObserve, namespacesToIgnore and kindsToObserve
+// of the ConfigMap. The objects of the kinds watched only for the policies are not captured by the ConfigMap.
+func configMapCaptures(config utils.WatchConfig, kubernetesObject client.Object, action moxv1alpha1.TrashedAction) bool {
+	if !slices.Contains(config.ActionsToWatch, string(action)) ||
+		slices.Contains(config.NamespacesToIgnore, kubernetesObject.GetNamespace()) {
+		return false
+	}
+
+	gvk := kubernetesObject.GetObjectKind().GroupVersionKind()
+	return slices.ContainsFunc(config.KindsToWatch, func(rawKind string) bool {
+		return utils.MatchesKindToWatch(rawKind, gvk)
+	})
+}
+
+func appendKindsToWatch(builder *ctrl.Builder, informers cache.Cache, mapper meta.RESTMapper, rawKinds []string,
+	predicates predicate.Predicate) []schema.GroupVersionKind {
+	watchedKinds := []schema.GroupVersionKind{}
 
 	// For each Kind, add a dynamica watch
 	for _, k := range rawKinds {
@@ -149,22 +344,20 @@ func appendKindsToWatch(builder *ctrl.Builder, configMapData v1.ConfigMap) {
 		if kind == "" {
 			continue
 		}
-		knownGVKs := utils.GetKnownKindsToWatch()
-		rgvk, ok := knownGVKs[strings.ToLower(kind)]
-		if !ok {
-			logger.Info("Kind not explicitly mapped; ignoring.", "kind", kind)
-			logger.Info("Kinds mapped are: " + utils.KnownGVKsAsString())
+		gvk, err := utils.ResolveKindToWatch(mapper, kind)
+		if err != nil {
+			logger.Error(err, "Unable to resolve kind; ignoring. Use kind.group/version for custom resources", "kind", kind)
 			continue
 		}
-
-		gvk := schema.GroupVersionKind{
-			Group:   rgvk.Group,
-			Version: rgvk.Version,
-			Kind:    kind,
+		if slices.Contains(watchedKinds, gvk) {
+			continue
 		}
-		logger.Info("Watching kind", "kind", kind)
-		u := &unstructured.Unstructured{}
-		u.SetGroupVersionKind(gvk)
-		builder.Watches(u, &handler.EnqueueRequestForObject{})
+
+		logger.Info("Watching kind", "kind", kind, "gvk", gvk.String())
+		var obj client.Object = newUnstructured(gvk)
+		builder.WatchesRawSource(source.Kind(informers, obj, &handler.EnqueueRequestForObject{}, predicates))
+		watchedKinds = append(watchedKinds, gvk)
 	}
+
+	return watchedKinds
 }
@@ -19,25 +19,34 @@ package controller
 import (
 	"bytes"
 	"context"
-	"regexp"
+	"os"
+	"path/filepath"
 	"time"
 	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/metrics"
+	"trashed-resources/internal/storage"
 	utils "trashed-resources/internal/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	log "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -56,7 +65,7 @@ var _ = Describe("TrashedResource Controller", func() {
 
 		BeforeEach(func() {
 			k8sClient = fake.NewClientBuilder().
-				WithStatusSubresource(&moxv1alpha1.TrashedResource{}).
+				WithStatusSubresource(&moxv1alpha2.TrashedResource{}).
 				Build()
 			By("creating the custom resource for the Kind trashedresources")
 			deployment := &appsv1.Deployment{
@@ -92,10 +101,10 @@ var _ = Describe("TrashedResource Controller", func() {
 			objectYAML := utils.MakeBodyManifest(deployment)
 			Expect(objectYAML).NotTo(BeNil())
 
-			resource := &moxv1alpha1.TrashedResource{}
+			resource := &moxv1alpha2.TrashedResource{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &moxv1alpha1.TrashedResource{
+				resource := &moxv1alpha2.TrashedResource{
 					TypeMeta: metav1.TypeMeta{
 						Kind:       "TrashedResource",
 						APIVersion: "mox.app.br/v1alpha1",
@@ -105,13 +114,9 @@ var _ = Describe("TrashedResource Controller", func() {
 						Name:         resourceName,
 						Namespace:    "default",
 					},
-					Spec: moxv1alpha1.TrashedResourceSpec{
-						Data: string(objectYAML),
-						KeepUntil: utils.GetTimetoKeepFromConfigMap(&utils.TRReconciler{
-							MinutesToKeep: "60",
-							HoursToKeep:   "0",
-							DaysToKeep:    "0",
-						}),
+					Spec: moxv1alpha2.TrashedResourceSpec{
+						Data:      string(objectYAML),
+						KeepUntil: metav1.NewTime(utils.Now().AddMinutes(60).Time),
 					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
@@ -119,7 +124,7 @@ var _ = Describe("TrashedResource Controller", func() {
 		})
 
 		AfterEach(func() {
-			resource := &moxv1alpha1.TrashedResource{}
+			resource := &moxv1alpha2.TrashedResource{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -138,11 +143,37 @@ var _ = Describe("TrashedResource Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should populate the status with the phase and the original object", func() {
+			controllerReconciler := &TrashedResourceReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			resource := &moxv1alpha2.TrashedResource{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Phase).To(Equal(moxv1alpha2.PhaseActive))
+			Expect(resource.Status.Original.Group).To(Equal("apps"))
+			Expect(resource.Status.Original.Kind).To(Equal("Deployment"))
+			Expect(resource.Status.Original.Name).To(Equal(resourceName))
+			Expect(resource.Status.ExpiresAt).NotTo(BeNil())
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", moxv1alpha2.ConditionCaptured),
+				HaveField("Status", metav1.ConditionTrue),
+			)))
+		})
 	})
 
 	Context("when setting up watches with appendKindsToWatch", func() {
 		var (
 			builder   *ctrl.Builder
+			mgr       ctrl.Manager
 			logBuffer bytes.Buffer
 		)
 
@@ -162,7 +193,8 @@ var _ = Describe("TrashedResource Controller", func() {
 
 			// A manager is needed to create a builder.
 			// We can use the cfg from the test suite.
-			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			var err error
+			mgr, err = ctrl.NewManager(cfg, ctrl.Options{
 				Scheme: k8sClient.Scheme(),
 				// Disable metrics to avoid port conflicts
 				Metrics: server.Options{BindAddress: "0"},
@@ -171,19 +203,19 @@ var _ = Describe("TrashedResource Controller", func() {
 			builder = ctrl.NewControllerManagedBy(mgr)
 		})
 
-		It("should add watches for valid kinds and ignore invalid ones", func() {
+		It("should add watches for any kind served by the cluster", func() {
 			cm := &corev1.ConfigMap{
 				Data: map[string]string{
 					"kindsToObserve": "Deployment; Pod",
 				},
 			}
 
-			appendKindsToWatch(builder, *cm)
+			appendKindsToWatch(builder, mgr.GetCache(), mgr.GetRESTMapper(), utils.GetKindsToWatchFromConfigMap(*cm), predicate.Funcs{})
 
 			logs := logBuffer.String()
 
-			Expect(logs).To(MatchRegexp(`Watching kind\s+\{"kind": "Deployment"\}`))
-			Expect(logs).To(MatchRegexp(`Kind not explicitly mapped; ignoring.\s+\{"kind": "Pod"\}`))
+			Expect(logs).To(MatchRegexp(`Watching kind\s+\{"kind": "Deployment", "gvk": "apps/v1, Kind=Deployment"\}`))
+			Expect(logs).To(MatchRegexp(`Watching kind\s+\{"kind": "Pod", "gvk": "/v1, Kind=Pod"\}`))
 		})
 
 		It("should handle empty or whitespace-only kind strings", func() {
@@ -193,7 +225,7 @@ var _ = Describe("TrashedResource Controller", func() {
 				},
 			}
 
-			appendKindsToWatch(builder, *cm)
+			appendKindsToWatch(builder, mgr.GetCache(), mgr.GetRESTMapper(), utils.GetKindsToWatchFromConfigMap(*cm), predicate.Funcs{})
 
 			logs := logBuffer.String()
 			Expect(logs).To(BeEmpty())
@@ -206,31 +238,41 @@ var _ = Describe("TrashedResource Controller", func() {
 				},
 			}
 
-			appendKindsToWatch(builder, *cm)
+			appendKindsToWatch(builder, mgr.GetCache(), mgr.GetRESTMapper(), utils.GetKindsToWatchFromConfigMap(*cm), predicate.Funcs{})
 
 			logs := logBuffer.String()
 
-			Expect(logs).To(MatchRegexp(`Watching kind\s+\{"kind": "Deployment"\}`))
-			Expect(logs).To(MatchRegexp(`Kind not explicitly mapped; ignoring.\s+\{"kind": "Pod"\}`))
-			expectedLog := "Kinds mapped are: " + regexp.QuoteMeta(utils.KnownGVKsAsString())
-			Expect(logs).To(MatchRegexp(expectedLog))
+			Expect(logs).To(MatchRegexp(`Watching kind\s+\{"kind": "Deployment"`))
+			Expect(logs).To(MatchRegexp(`Watching kind\s+\{"kind": "Pod"`))
+		})
+
+		It("should resolve fully qualified kinds, including custom resources", func() {
+			cm := &corev1.ConfigMap{
+				Data: map[string]string{
+					"kindsToObserve": "Deployment.apps/v1; TrashedResource.mox.app.br/v1alpha1",
+				},
+			}
+
+			appendKindsToWatch(builder, mgr.GetCache(), mgr.GetRESTMapper(), utils.GetKindsToWatchFromConfigMap(*cm), predicate.Funcs{})
 
+			logs := logBuffer.String()
+			Expect(logs).To(ContainSubstring("apps/v1, Kind=Deployment"))
+			Expect(logs).To(ContainSubstring("mox.app.br/v1alpha1, Kind=TrashedResource"))
 		})
 
-		It("should not add any watches if kindsToObserve is not present in known kinds", func() {
+		It("should log an error and not add watches for kinds unknown to the cluster", func() {
 			cm := &corev1.ConfigMap{
 				Data: map[string]string{
-					"kindsToObserve": "someValue",
+					"kindsToObserve": "someValue; Application.argoproj.io/v1alpha1",
 				},
 			}
 
-			appendKindsToWatch(builder, *cm)
+			appendKindsToWatch(builder, mgr.GetCache(), mgr.GetRESTMapper(), utils.GetKindsToWatchFromConfigMap(*cm), predicate.Funcs{})
 
 			logs := logBuffer.String()
-			// Expect(logs).To(ContainSubstring("Kind not explicitly mapped; ignoring."))
-			Expect(logs).To(MatchRegexp(`Kind not explicitly mapped; ignoring.\s+\{"kind": "someValue"\}`))
-			expectedLog := "Kinds mapped are: " + regexp.QuoteMeta(utils.KnownGVKsAsString())
-			Expect(logs).To(MatchRegexp(expectedLog))
+			Expect(logs).To(MatchRegexp(`Unable to resolve kind; ignoring.*\{"kind": "someValue"`))
+			Expect(logs).To(MatchRegexp(`Unable to resolve kind; ignoring.*\{"kind": "Application.argoproj.io/v1alpha1"`))
+			Expect(logs).NotTo(ContainSubstring("Watching kind"))
 		})
 	})
 
@@ -241,7 +283,7 @@ var _ = Describe("TrashedResource Controller", func() {
 
 		BeforeEach(func() {
 			k8sClient = fake.NewClientBuilder().
-				WithStatusSubresource(&moxv1alpha1.TrashedResource{}).
+				WithStatusSubresource(&moxv1alpha2.TrashedResource{}).
 				Build()
 
 			reconciler = &TrashedResourceReconciler{
@@ -253,40 +295,45 @@ var _ = Describe("TrashedResource Controller", func() {
 		It("should delete the resource if it is expired", func() {
 			expiredName := "expired-resource"
 			// Create a resource with KeepUntil in the past
-			expiredTR := &moxv1alpha1.TrashedResource{
+			expiredTR := &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      expiredName,
 					Namespace: "default",
 				},
-				Spec: moxv1alpha1.TrashedResourceSpec{
+				Spec: moxv1alpha2.TrashedResourceSpec{
 					Data:      "some-data",
-					KeepUntil: time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+					KeepUntil: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
 				},
 			}
 			Expect(k8sClient.Create(ctx, expiredTR)).To(Succeed())
+			expiredBefore := testutil.ToFloat64(metrics.Expired.WithLabelValues("default"))
+			recorder := events.NewFakeRecorder(1)
+			reconciler.Recorder = recorder
 
 			// Reconcile
 			_, err := reconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: types.NamespacedName{Name: expiredName, Namespace: "default"},
 			})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(metrics.Expired.WithLabelValues("default"))).To(Equal(expiredBefore + 1))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Expired Retention ended at")))
 
 			// Verify deletion
-			err = k8sClient.Get(ctx, types.NamespacedName{Name: expiredName, Namespace: "default"}, &moxv1alpha1.TrashedResource{})
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: expiredName, Namespace: "default"}, &moxv1alpha2.TrashedResource{})
 			Expect(errors.IsNotFound(err)).To(BeTrue())
 		})
 
 		It("should requeue if the resource is not expired", func() {
 			futureName := "future-resource"
 			// Create a resource with KeepUntil in the future
-			futureTR := &moxv1alpha1.TrashedResource{
+			futureTR := &moxv1alpha2.TrashedResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      futureName,
 					Namespace: "default",
 				},
-				Spec: moxv1alpha1.TrashedResourceSpec{
+				Spec: moxv1alpha2.TrashedResourceSpec{
 					Data:      "some-data",
-					KeepUntil: time.Now().Add(1 * time.Hour).Format(time.RFC3339),
+					KeepUntil: metav1.NewTime(time.Now().Add(1 * time.Hour)),
 				},
 			}
 			Expect(k8sClient.Create(ctx, futureTR)).To(Succeed())
@@ -302,6 +349,87 @@ var _ = Describe("TrashedResource Controller", func() {
 			Expect(result.RequeueAfter).To(BeNumerically("<=", 1*time.Hour+time.Minute))
 		})
 
+		It("should archive the resource before deleting it if it is expired", func() {
+			archiveDir := GinkgoT().TempDir()
+			configure(reconciler, func(config *utils.WatchConfig) {
+				config.ArchiveSink = "file://" + archiveDir
+				config.ArchiveFormat = utils.ArchiveFormatYAML
+			})
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "archived-resource", Namespace: "default"},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data:      "some-data",
+					KeepUntil: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+				},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "archived-resource", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			archived, err := filepath.Glob(filepath.Join(archiveDir, "default", "archived-resource*.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archived).To(HaveLen(1))
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "archived-resource", Namespace: "default"}, &moxv1alpha2.TrashedResource{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should archive the expired data Secret mode resource redacted without encryptionKeySecret", func() {
+			archiveDir := GinkgoT().TempDir()
+			configure(reconciler, func(config *utils.WatchConfig) {
+				config.ArchiveSink = "file://" + archiveDir
+				config.ArchiveFormat = utils.ArchiveFormatYAML
+			})
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "secret-resource", Namespace: "default"},
+				Data:       map[string][]byte{"manifest": []byte("apiVersion: v1\nkind: Secret\ndata:\n  password: czNjcjN0\n")},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "secret-resource", Namespace: "default"},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data:           "apiVersion: v1\nkind: Secret\ndata:\n  password: REDACTED\n",
+					DataProtection: moxv1alpha2.DataSecret,
+					DataSecretRef:  &moxv1alpha2.SecretKeyReference{Name: "secret-resource", Key: "manifest"},
+					KeepUntil:      metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+				},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "secret-resource", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			archived, err := filepath.Glob(filepath.Join(archiveDir, "default", "secret-resource*.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archived).To(HaveLen(1))
+			content, err := os.ReadFile(archived[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("dataProtection: Redacted"))
+			Expect(string(content)).NotTo(ContainSubstring("czNjcjN0"))
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "secret-resource", Namespace: "default"},
+				&moxv1alpha2.TrashedResource{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should keep the expired resource if it can not be archived", func() {
+			configure(reconciler, func(config *utils.WatchConfig) { config.ArchiveSink = "s3://" })
+			Expect(k8sClient.Create(ctx, &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "unarchived-resource", Namespace: "default"},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					Data:      "some-data",
+					KeepUntil: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+				},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "unarchived-resource", Namespace: "default"},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "unarchived-resource", Namespace: "default"},
+				&moxv1alpha2.TrashedResource{})).To(Succeed())
+		})
+
 		It("should ignore if resource is not found", func() {
 			// Reconcile a non-existent resource
 			result, err := reconciler.Reconcile(ctx, reconcile.Request{
@@ -386,19 +514,82 @@ var _ = Describe("TrashedResource Controller", func() {
 			})
 			_ = reconciler.SetupWithManager(mgr)
 			// Verify configuration loaded into reconciler
-			Expect(reconciler.KindsToWatch).To(ConsistOf("Deployment", "Secret"))
-			Expect(reconciler.ActionsToWatch).To(ConsistOf("delete"))
-			Expect(reconciler.NamespacesToIgnore).To(ConsistOf("kube-system"))
-			Expect(reconciler.MinutesToKeep).To(Equal("30"))
-			Expect(reconciler.HoursToKeep).To(Equal("1"))
+			config := reconciler.CurrentConfig()
+			Expect(config.KindsToWatch).To(ConsistOf("Deployment", "Secret"))
+			Expect(config.ActionsToWatch).To(ConsistOf("delete"))
+			Expect(config.NamespacesToIgnore).To(ConsistOf("kube-system"))
+			Expect(config.MinutesToKeep).To(Equal("30"))
+			Expect(config.HoursToKeep).To(Equal("1"))
 		})
 
 		It("should use default configuration when ConfigMap is missing", func() {
 			_ = reconciler.SetupWithManager(mgr)
 
 			// When configmap not set kindsToObserve then use as defined in utils.GetAllConfigsFromConfigMap
-			Expect(reconciler.KindsToWatch).To(ContainElements("Deployment", "Secret", "ConfigMap"))
-			Expect(reconciler.ActionsToWatch).To(ContainElement("delete"))
+			config := reconciler.CurrentConfig()
+			Expect(config.KindsToWatch).To(ContainElements("Deployment", "Secret", "ConfigMap"))
+			Expect(config.ActionsToWatch).To(ContainElement("delete"))
+		})
+	})
+
+	Context("When a TrashedResource with data in the storage backend expires", func() {
+		It("should delete it from the storage backend and release it through the manager", func() {
+			dir := GinkgoT().TempDir()
+			backendURL := "file://" + dir
+			Expect(storage.NewFilesystem(dir).Put(ctx, "default/expired-external", []byte("kind: ConfigMap"))).To(Succeed())
+
+			originalUtilsGetAllConfigsFromConfigMap := utils.GetAllConfigsFromConfigMap
+			utils.GetAllConfigsFromConfigMap = func(mgr ctrl.Manager, cmName string) corev1.ConfigMap {
+				return corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "mock-cm", Namespace: utils.ControllerNamespace},
+					Data: map[string]string{
+						"kindsToObserve":   "ConfigMap",
+						"actionsToObserve": "delete",
+						"storageBackend":   backendURL,
+					},
+				}
+			}
+			DeferCleanup(func() {
+				utils.GetAllConfigsFromConfigMap = originalUtilsGetAllConfigsFromConfigMap
+			})
+
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:     k8sClient.Scheme(),
+				Metrics:    server.Options{BindAddress: "0"},
+				Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect((&TrashedResourceReconciler{
+				Client: mgr.GetClient(),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr)).To(Succeed())
+
+			mgrCtx, cancel := context.WithCancel(ctx)
+			DeferCleanup(cancel)
+			go func() {
+				defer GinkgoRecover()
+				Expect(mgr.Start(mgrCtx)).To(Succeed())
+			}()
+
+			expired := &moxv1alpha2.TrashedResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "expired-external",
+					Namespace:  "default",
+					Finalizers: []string{moxv1alpha2.ExternalDataFinalizer},
+				},
+				Spec: moxv1alpha2.TrashedResourceSpec{
+					External:  &moxv1alpha2.ExternalData{URL: backendURL, Key: "default/expired-external"},
+					KeepUntil: metav1.NewTime(time.Now().Add(-time.Hour)),
+				},
+			}
+			Expect(k8sClient.Create(ctx, expired)).To(Succeed())
+
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(expired),
+					&moxv1alpha2.TrashedResource{}))
+			}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+			_, err = storage.NewFilesystem(dir).Get(ctx, "default/expired-external")
+			Expect(err).To(MatchError(storage.ErrNotFound))
 		})
 	})
 
@@ -411,18 +602,17 @@ var _ = Describe("TrashedResource Controller", func() {
 		BeforeEach(func() {
 			fakeClient = fake.NewClientBuilder().
 				WithScheme(k8sClient.Scheme()).
-				WithStatusSubresource(&moxv1alpha1.TrashedResource{}).
+				WithStatusSubresource(&moxv1alpha2.TrashedResource{}).
 				Build()
 
-			reconciler = &TrashedResourceReconciler{
-				Client:             fakeClient,
-				Scheme:             k8sClient.Scheme(),
+			reconciler = newConfiguredReconciler(fakeClient, utils.WatchConfig{
+				KindsToWatch:       []string{"Deployment"},
 				ActionsToWatch:     []string{"update", "delete"},
 				NamespacesToIgnore: []string{"kube-system"},
-			}
-			reconciler.MinutesToKeep = "1"
-			reconciler.HoursToKeep = "1"
-			reconciler.DaysToKeep = "0"
+				MinutesToKeep:      "1",
+				HoursToKeep:        "1",
+				DaysToKeep:         "0",
+			})
 		})
 
 		It("should trigger manifest creation on valid update", func() {
@@ -438,7 +628,7 @@ var _ = Describe("TrashedResource Controller", func() {
 			// Expect false return but side effect (TrashedResource creation)
 			Expect(reconciler.HandleUpdate(e, fakeClient)).To(BeTrue())
 
-			trList := &moxv1alpha1.TrashedResourceList{}
+			trList := &moxv1alpha2.TrashedResourceList{}
 			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
 			Expect(trList.Items).To(HaveLen(1))
 		})
@@ -451,7 +641,7 @@ var _ = Describe("TrashedResource Controller", func() {
 			e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: oldObj.DeepCopy()}
 
 			Expect(reconciler.HandleUpdate(e, fakeClient)).To(BeFalse())
-			trList := &moxv1alpha1.TrashedResourceList{}
+			trList := &moxv1alpha2.TrashedResourceList{}
 			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
 			Expect(trList.Items).To(BeEmpty())
 		})
@@ -472,7 +662,7 @@ var _ = Describe("TrashedResource Controller", func() {
 			e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
 
 			Expect(reconciler.HandleUpdate(e, fakeClient)).To(BeTrue())
-			trList := &moxv1alpha1.TrashedResourceList{}
+			trList := &moxv1alpha2.TrashedResourceList{}
 			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
 			Expect(trList.Items).ToNot(BeEmpty())
 		})
@@ -488,9 +678,105 @@ var _ = Describe("TrashedResource Controller", func() {
 			// Expect true return and side effect (TrashedResource creation)
 			Expect(reconciler.HandleDelete(e, fakeClient)).To(BeTrue())
 
-			trList := &moxv1alpha1.TrashedResourceList{}
+			trList := &moxv1alpha2.TrashedResourceList{}
+			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
+			Expect(trList.Items).To(HaveLen(1))
+		})
+	})
+
+	Context("When handling events with TrashedResourcePolicies", func() {
+		var (
+			reconciler *TrashedResourceReconciler
+			fakeClient client.Client
+		)
+
+		BeforeEach(func() {
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}},
+				}).
+				Build()
+
+			reconciler = newConfiguredReconciler(fakeClient, utils.WatchConfig{
+				KindsToWatch:   []string{"Deployment"},
+				ActionsToWatch: []string{"delete"},
+				MinutesToKeep:  "10",
+				HoursToKeep:    "0",
+				DaysToKeep:     "0",
+			})
+			reconciler.applyPolicies([]moxv1alpha1.TrashedResourcePolicy{{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-secrets"},
+				Spec: moxv1alpha1.TrashedResourcePolicySpec{
+					Kinds:             []moxv1alpha1.PolicyKind{{Kind: "Secret"}},
+					Actions:           []moxv1alpha1.TrashedAction{moxv1alpha1.ActionDelete, moxv1alpha1.ActionUpdate},
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				},
+			}})
+		})
+
+		It("should capture objects matched by a policy and record the policy", func() {
+			secret := &corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "prod"},
+			}
+
+			Expect(reconciler.HandleDelete(event.DeleteEvent{Object: secret}, fakeClient)).To(BeTrue())
+
+			trList := &moxv1alpha2.TrashedResourceList{}
+			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
+			Expect(trList.Items).To(HaveLen(1))
+			Expect(trList.Items[0].Labels).To(HaveKeyWithValue(moxv1alpha1.PolicyLabel, "prod-secrets"))
+		})
+
+		It("should not capture the Secrets that hold the data of TrashedResources", func() {
+			secret := &corev1.Secret{
+				TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "trashed-deleted-secret-db-20260301-230159",
+					Namespace: "prod",
+					Labels:    map[string]string{moxv1alpha2.DataSecretLabel: "trashed-deleted-secret-db-20260301-230159"},
+				},
+			}
+
+			Expect(reconciler.HandleDelete(event.DeleteEvent{Object: secret}, fakeClient)).To(BeFalse())
+		})
+
+		It("should not capture kinds of the policies with the ConfigMap rules", func() {
+			secret := &corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			}
+
+			Expect(reconciler.HandleDelete(event.DeleteEvent{Object: secret}, fakeClient)).To(BeFalse())
+		})
+
+		It("should not capture the namespaces excluded by a policy without kindsToObserve", func() {
+			configure(reconciler, func(config *utils.WatchConfig) { config.KindsToWatch = nil })
+			secret := &corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			}
+
+			Expect(reconciler.HandleDelete(event.DeleteEvent{Object: secret}, fakeClient)).To(BeFalse())
+
+			trList := &moxv1alpha2.TrashedResourceList{}
+			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
+			Expect(trList.Items).To(BeEmpty())
+		})
+
+		It("should fall back to the ConfigMap rules", func() {
+			deployment := &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			}
+
+			Expect(reconciler.HandleDelete(event.DeleteEvent{Object: deployment}, fakeClient)).To(BeTrue())
+
+			trList := &moxv1alpha2.TrashedResourceList{}
 			Expect(fakeClient.List(context.Background(), trList)).To(Succeed())
 			Expect(trList.Items).To(HaveLen(1))
+			Expect(trList.Items[0].Labels).NotTo(HaveKey(moxv1alpha1.PolicyLabel))
 		})
 	})
 
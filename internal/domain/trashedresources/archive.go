@@ -0,0 +1,74 @@
+package trashedresources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/archive"
+	utils "trashed-resources/internal/utils"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ArchiveExpired guarda o TrashedResource expirado no archiveSink, quando configurado, antes de ele ser
+// deletado. O objeto guardado no storageBackend é copiado para o spec.data e o spec.encrypted, para o arquivo
+// não depender do storageBackend. Retorna erro quando não foi possível arquivar, e nesse caso o TrashedResource
+// não deve ser deletado.
+func ArchiveExpired(ctx context.Context, c client.Client, config utils.WatchConfig,
+	trashed *moxv1alpha2.TrashedResource) error {
+	if config.ArchiveSink == "" {
+		return nil
+	}
+	sink, err := archive.Open(config.ArchiveSink, config.ArchiveFormat)
+	if err != nil {
+		return err
+	}
+	return ArchiveTo(ctx, c, config, sink, trashed)
+}
+
+// ArchiveTo guarda o TrashedResource no sink, sem os campos de metadata gerenciados pelo cluster. O objeto do
+// Secret do modo secret, que é deletado com o TrashedResource, é criptografado com o encryptionKeySecret no
+// spec.encrypted. Sem o encryptionKeySecret o TrashedResource é arquivado redigido, para a expiração seguir.
+func ArchiveTo(ctx context.Context, c client.Client, config utils.WatchConfig, sink archive.Sink,
+	trashed *moxv1alpha2.TrashedResource) error {
+	archived := trashed.DeepCopy()
+	archived.ManagedFields = nil
+	if archived.Spec.External != nil {
+		data, encrypted, err := backendData(ctx, config, trashed)
+		if err != nil {
+			return err
+		}
+		archived.Spec.Data = data
+		if archived.Spec.Encrypted != nil {
+			archived.Spec.Encrypted.Data = encrypted
+		}
+		archived.Spec.External = nil
+		archived.Finalizers = slices.DeleteFunc(archived.Finalizers, func(finalizer string) bool {
+			return finalizer == moxv1alpha2.ExternalDataFinalizer
+		})
+	}
+	if archived.Spec.DataProtection == moxv1alpha2.DataSecret && archived.Spec.DataSecretRef != nil &&
+		config.EncryptionKeySecret == "" {
+		logger.Info("Warning: encryptionKeySecret is not set, archiving the TrashedResource without its data Secret",
+			"name", trashed.Name, "namespace", trashed.Namespace)
+		archived.Spec.DataProtection = moxv1alpha2.DataRedacted
+		archived.Spec.DataSecretRef = nil
+	}
+	if archived.Spec.DataProtection == moxv1alpha2.DataSecret && archived.Spec.DataSecretRef != nil {
+		encoded, err := secretKeyValue(ctx, c, *archived.Spec.DataSecretRef, trashed.Namespace)
+		if err != nil {
+			return err
+		}
+		encrypted, err := encryptWithKeyRef(ctx, c, encryptionKeyRef(config), encoded)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt the data Secret of TrashedResource %s/%s to archive it: %w",
+				trashed.Namespace, trashed.Name, err)
+		}
+		archived.Spec.DataProtection = moxv1alpha2.DataEncrypted
+		archived.Spec.Encrypted = encrypted
+		archived.Spec.DataSecretRef = nil
+	}
+	return sink.Archive(ctx, archived)
+}
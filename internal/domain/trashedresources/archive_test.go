@@ -0,0 +1,109 @@
+package trashedresources
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type recordingSink struct {
+	archived []*moxv1alpha2.TrashedResource
+}
+
+func (s *recordingSink) Archive(_ context.Context, trashed *moxv1alpha2.TrashedResource) error {
+	s.archived = append(s.archived, trashed)
+	return nil
+}
+
+func TestArchiveTo(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	root, other := t.TempDir(), t.TempDir()
+	g.Expect(storage.NewFilesystem(root).Put(ctx, "default/trashed-big", []byte("stored-data"))).To(Succeed())
+	g.Expect(storage.NewFilesystem(other).Put(ctx, "token", []byte("other-data"))).To(Succeed())
+	trashed := &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "trashed-big",
+			Namespace:     "default",
+			Finalizers:    []string{moxv1alpha2.ExternalDataFinalizer},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "manager"}},
+		},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			Encoding: moxv1alpha2.DataEncodingGzip,
+			// O spec.external é escrito pelo usuário, o objeto é lido do storageBackend configurado
+			External: &moxv1alpha2.ExternalData{URL: "file://" + other, Key: "token"},
+		},
+	}
+
+	sink := &recordingSink{}
+	config := utils.WatchConfig{StorageBackend: "file://" + root}
+	g.Expect(ArchiveTo(ctx, newProtectionClient(), config, sink, trashed)).To(Succeed())
+	g.Expect(sink.archived).To(HaveLen(1))
+	// O objeto do storageBackend é copiado, ainda codificado, para o spec.data
+	g.Expect(sink.archived[0].Spec.Data).To(Equal("stored-data"))
+	g.Expect(sink.archived[0].Spec.Encoding).To(Equal(moxv1alpha2.DataEncodingGzip))
+	g.Expect(sink.archived[0].Spec.External).To(BeNil())
+	g.Expect(sink.archived[0].Finalizers).To(BeEmpty())
+	g.Expect(sink.archived[0].ManagedFields).To(BeEmpty())
+	// O TrashedResource não é alterado
+	g.Expect(trashed.Spec.External).NotTo(BeNil())
+	g.Expect(trashed.Finalizers).To(HaveLen(1))
+}
+
+func TestArchiveTo_DataSecret(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "trashed-resources-key", Namespace: utils.ControllerNamespace},
+		Data:       map[string][]byte{utils.EncryptionKeySecretKey: bytes.Repeat([]byte{1}, 32)},
+	}
+	c := newProtectionClient(keySecret)
+	reconciler := newReconciler(utils.WatchConfig{MinutesToKeep: "60"})
+	trashed := captureSecret(g, c, reconciler)
+	g.Expect(trashed.Spec.DataProtection).To(Equal(moxv1alpha2.DataSecret))
+
+	// Sem o encryptionKeySecret o objeto do Secret não pode ser arquivado, o TrashedResource é arquivado redigido
+	sink := &recordingSink{}
+	g.Expect(ArchiveTo(ctx, c, utils.WatchConfig{}, sink, &trashed)).To(Succeed())
+	g.Expect(sink.archived).To(HaveLen(1))
+	g.Expect(sink.archived[0].Spec.DataProtection).To(Equal(moxv1alpha2.DataRedacted))
+	g.Expect(sink.archived[0].Spec.DataSecretRef).To(BeNil())
+	g.Expect(sink.archived[0].Spec.Data).NotTo(ContainSubstring("czNjcjN0"))
+	sink.archived = nil
+
+	// O Secret é deletado com o TrashedResource, o objeto é arquivado criptografado
+	config := utils.WatchConfig{EncryptionKeySecret: "trashed-resources-key"}
+	g.Expect(ArchiveTo(ctx, c, config, sink, &trashed)).To(Succeed())
+	g.Expect(sink.archived).To(HaveLen(1))
+	archived := sink.archived[0]
+	g.Expect(archived.Spec.DataProtection).To(Equal(moxv1alpha2.DataEncrypted))
+	g.Expect(archived.Spec.DataSecretRef).To(BeNil())
+	g.Expect(archived.Spec.Encrypted.KeyRef.Name).To(Equal("trashed-resources-key"))
+	manifest, err := RestorableManifest(ctx, c, archived, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).To(ContainSubstring("password: czNjcjN0"))
+}
+
+func TestArchiveExpired(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	trashed := &moxv1alpha2.TrashedResource{ObjectMeta: metav1.ObjectMeta{Name: "trashed", Namespace: "default"}}
+
+	// Sem archiveSink não há o que fazer
+	g.Expect(ArchiveExpired(context.Background(), c, utils.WatchConfig{}, trashed)).To(Succeed())
+
+	root := t.TempDir()
+	config := utils.WatchConfig{ArchiveSink: "file://" + root, ArchiveFormat: utils.ArchiveFormatNDJSON}
+	g.Expect(ArchiveExpired(context.Background(), c, config, trashed)).To(Succeed())
+
+	config.ArchiveSink = "ftp://archive"
+	g.Expect(ArchiveExpired(context.Background(), c, config, trashed)).To(MatchError(ContainSubstring("invalid storage backend")))
+}
@@ -0,0 +1,106 @@
+package trashedresources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons dos Events emitidos para os objetos capturados e para os TrashedResources.
+const (
+	EventReasonTrashed       = "Trashed"
+	EventReasonCaptureFailed = "CaptureFailed"
+	EventReasonExpired       = "Expired"
+	EventReasonRestored      = "Restored"
+	EventReasonRestoreFailed = "RestoreFailed"
+
+	// RestoreEventsController é o reportingController dos Events de restore, criados pelo plugin.
+	RestoreEventsController = "mox.app.br/kubectl-trashedresources"
+)
+
+// RecordEvent emite um Event sobre regarding, no namespace dele, quando recorder não é nil.
+func RecordEvent(recorder events.EventRecorder, regarding, related runtime.Object, eventType, reason, action,
+	note string, args ...any) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(regarding, related, eventType, reason, action, note, args...)
+}
+
+// captureFailed registra a falha ao capturar o objeto na métrica e num Event sobre o objeto, visível no
+// namespace dele.
+func captureFailed(recorder events.EventRecorder, kubernetesObject client.Object, kind, action, reason string, err error) {
+	metrics.CaptureErrors.WithLabelValues(kind, action, reason).Inc()
+	RecordEvent(recorder, kubernetesObject, nil, corev1.EventTypeWarning, EventReasonCaptureFailed, "Capture",
+		"Failed to capture the %s %s of %s into a TrashedResource (%s): %v", action, kind, kubernetesObject.GetName(),
+		reason, err)
+}
+
+// RecordRestoreEvent cria o Event Restored, ou RestoreFailed quando restoreErr não é nil, sobre o
+// TrashedResource e relacionado ao objeto restaurado, que pode ser nil. Ele é criado diretamente com o client,
+// porque quem restaura é o plugin e não o controller.
+func RecordRestoreEvent(ctx context.Context, c client.Client, trashed *moxv1alpha2.TrashedResource,
+	restored client.Object, restoreErr error) error {
+	reportingInstance, err := os.Hostname()
+	if err != nil || reportingInstance == "" {
+		reportingInstance = "kubectl"
+	}
+	event := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: trashed.Name + ".",
+			Namespace:    trashed.Namespace,
+		},
+		EventTime:           metav1.NowMicro(),
+		ReportingController: RestoreEventsController,
+		ReportingInstance:   reportingInstance,
+		Action:              "Restore",
+		Type:                corev1.EventTypeNormal,
+		Reason:              EventReasonRestored,
+		Regarding: corev1.ObjectReference{
+			APIVersion:      moxv1alpha2.GroupVersion.String(),
+			Kind:            "TrashedResource",
+			Namespace:       trashed.Namespace,
+			Name:            trashed.Name,
+			UID:             trashed.UID,
+			ResourceVersion: trashed.ResourceVersion,
+		},
+	}
+	if restored != nil {
+		gvk := restored.GetObjectKind().GroupVersionKind()
+		event.Related = &corev1.ObjectReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  restored.GetNamespace(),
+			Name:       restored.GetName(),
+			UID:        restored.GetUID(),
+		}
+		event.Note = fmt.Sprintf("Restored %s %s", gvk.Kind, objectName(restored))
+	}
+	if restoreErr != nil {
+		event.Type = corev1.EventTypeWarning
+		event.Reason = EventReasonRestoreFailed
+		event.Note = restoreErr.Error()
+	}
+	// O note dos Events tem no máximo 1kB
+	if len(event.Note) > 1024 {
+		event.Note = event.Note[:1024]
+	}
+	return c.Create(ctx, event)
+}
+
+func objectName(object client.Object) string {
+	if object.GetNamespace() == "" {
+		return object.GetName()
+	}
+	return object.GetNamespace() + "/" + object.GetName()
+}
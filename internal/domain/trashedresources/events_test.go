@@ -0,0 +1,85 @@
+package trashedresources
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCreateOrUpdatedManifest_Events(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	recorder := events.NewFakeRecorder(10)
+
+	reconciler := newReconciler(utils.WatchConfig{MinutesToKeep: "60"})
+	reconciler.Recorder = recorder
+	g.Expect(CreateOrUpdatedManifest(c, newLargeConfigMap(), reconciler, "deleted", nil)).To(BeTrue())
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Normal Trashed Captured the delete ConfigMap default/large, kept until ")))
+
+	// Sem storageBackend o objeto grande não é capturado
+	reconciler = newReconciler(utils.WatchConfig{MinutesToKeep: "60", MaxInlineSize: 64})
+	reconciler.Recorder = recorder
+	g.Expect(CreateOrUpdatedManifest(c, newLargeConfigMap(), reconciler, "updated", nil)).To(BeFalse())
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Warning CaptureFailed Failed to capture the update ConfigMap of large into a TrashedResource (store)"),
+		ContainSubstring("no storageBackend is configured"),
+	)))
+
+	// Sem recorder nenhum Event é emitido
+	other := newLargeConfigMap()
+	other.Name = "other"
+	g.Expect(CreateOrUpdatedManifest(c, other, newReconciler(utils.WatchConfig{MinutesToKeep: "60"}), "deleted",
+		nil)).To(BeTrue())
+	g.Expect(recorder.Events).NotTo(Receive())
+}
+
+func TestRecordRestoreEvent(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(eventsv1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	trashed := &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "trashed-deleted-configmap-cfg", Namespace: "team-a", UID: "uid"},
+	}
+	restored := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "debug"},
+	}
+
+	g.Expect(RecordRestoreEvent(ctx, c, trashed, restored, nil)).To(Succeed())
+	g.Expect(RecordRestoreEvent(ctx, c, trashed, nil, errors.New(strings.Repeat("x", 2048)))).To(Succeed())
+
+	list := &eventsv1.EventList{}
+	g.Expect(c.List(ctx, list, client.InNamespace("team-a"))).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(2))
+	for _, event := range list.Items {
+		g.Expect(event.Regarding.Kind).To(Equal("TrashedResource"))
+		g.Expect(event.Regarding.UID).To(BeEquivalentTo("uid"))
+		g.Expect(event.ReportingController).To(Equal(RestoreEventsController))
+		g.Expect(event.ReportingInstance).NotTo(BeEmpty())
+		if event.Reason == EventReasonRestored {
+			g.Expect(event.Type).To(Equal(corev1.EventTypeNormal))
+			g.Expect(event.Note).To(Equal("Restored ConfigMap debug/cfg"))
+			g.Expect(event.Related.Namespace).To(Equal("debug"))
+		} else {
+			g.Expect(event.Reason).To(Equal(EventReasonRestoreFailed))
+			g.Expect(event.Type).To(Equal(corev1.EventTypeWarning))
+			g.Expect(event.Note).To(HaveLen(1024))
+			g.Expect(event.Related).To(BeNil())
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package trashedresources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// OriginalHash retorna o hash do kind, namespace e nome do objeto original, usado no label
+// mox.app.br/original-hash para agrupar as revisões capturadas do mesmo objeto. O kind não
+// diferencia maiúsculas de minúsculas, como no kubectl.
+func OriginalHash(kind, namespace, name string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(kind) + "/" + namespace + "/" + name))
+	// Os valores de labels têm no máximo 63 caracteres.
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,18 @@
+package trashedresources
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestOriginalHash(t *testing.T) {
+	g := NewWithT(t)
+
+	hash := OriginalHash("ConfigMap", "default", "app-config")
+	g.Expect(validation.IsValidLabelValue(hash)).To(BeEmpty())
+	g.Expect(OriginalHash("configmap", "default", "app-config")).To(Equal(hash))
+	g.Expect(OriginalHash("ConfigMap", "other", "app-config")).NotTo(Equal(hash))
+	g.Expect(OriginalHash("Secret", "default", "app-config")).NotTo(Equal(hash))
+}
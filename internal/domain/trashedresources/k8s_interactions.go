@@ -2,15 +2,22 @@ package trashedresources
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
 
+	"trashed-resources/internal/metrics"
 	utils "trashed-resources/internal/utils"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -19,10 +26,10 @@ var logger = log.Log
 
 // TrashedResourceInteractor define a interface para interagir com objetos TrashedResource.
 type TrashedResourceInteractor interface {
-	Get(ctx context.Context, name, namespace string) (*moxv1alpha1.TrashedResource, error)
-	List(ctx context.Context, namespace string) (*moxv1alpha1.TrashedResourceList, error)
-	Create(ctx context.Context, resource *moxv1alpha1.TrashedResource) error
-	Update(ctx context.Context, resource *moxv1alpha1.TrashedResource) error
+	Get(ctx context.Context, name, namespace string) (*moxv1alpha2.TrashedResource, error)
+	List(ctx context.Context, namespace string) (*moxv1alpha2.TrashedResourceList, error)
+	Create(ctx context.Context, resource *moxv1alpha2.TrashedResource) error
+	Update(ctx context.Context, resource *moxv1alpha2.TrashedResource) error
 	Delete(ctx context.Context, name, namespace string) error
 }
 
@@ -32,47 +39,166 @@ type trashedResourceInteractor struct {
 }
 type TRReconciler utils.TrashedResourceReconciler
 
-func CreateOrUpdatedManifest(c client.Client, kubernetesObject client.Object, resourceReconciler *TRReconciler, actionType string) bool {
+// CreateOrUpdatedManifest cria o TrashedResource do objeto com CaptureManifest, sem o usuário que fez a
+// operação, e retorna se ele foi criado.
+func CreateOrUpdatedManifest(c client.Client, kubernetesObject client.Object, resourceReconciler *TRReconciler,
+	actionType string, policy *moxv1alpha1.TrashedResourcePolicy) bool {
+	return CaptureManifest(c, kubernetesObject, resourceReconciler, actionType, CaptureOptions{Policy: policy}) == nil
+}
+
+// CaptureOptions completa o TrashedResource criado por CaptureManifest.
+type CaptureOptions struct {
+	// Policy, quando não é nil, dá o label da policy ao TrashedResource.
+	Policy *moxv1alpha1.TrashedResourcePolicy
+	// RequestedBy é o usuário que deletou ou alterou o objeto, quando conhecido.
+	RequestedBy string
+	// Reason vai no label mox.app.br/capture-reason quando não é vazio.
+	Reason string
+	// Redacted indica que o objeto já está sem os valores sensíveis, eg. o do offlineIndex.
+	Redacted bool
+}
+
+// boundedName limita o nome ao tamanho máximo dos nomes de objetos, trocando o final pelo hash do nome
+// completo, para nomes diferentes continuarem diferentes.
+func boundedName(name string) string {
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:4])
+	return strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(hash)-1], "-.") + "-" + hash
+}
+
+// CaptureManifest cria o TrashedResource do objeto. A retention é escolhida por ResolveRetention e a regra
+// usada fica na anotação mox.app.br/retention-rule. Os valores sensíveis são protegidos conforme
+// dataProtectionByKind e objetos grandes são comprimidos ou guardados no storageBackend por storeData.
+func CaptureManifest(c client.Client, kubernetesObject client.Object, resourceReconciler *TRReconciler,
+	actionType string, options CaptureOptions) error {
 	ctx := context.Background()
+	policy := options.Policy
 	trInteractor := trashedResourceInteractor{client: c}
+	kind := kubernetesObject.GetObjectKind().GroupVersionKind().Kind
+	action := string(ActionFromType(actionType))
+	recorder := resourceReconciler.Recorder
 	objectYAML := utils.MakeBodyManifest(kubernetesObject)
 	if objectYAML == nil {
-		return false
+		err := fmt.Errorf("failed to encode the object")
+		captureFailed(recorder, kubernetesObject, kind, action, metrics.CaptureErrorEncode, err)
+		return err
+	}
+	config := (*utils.TrashedResourceReconciler)(resourceReconciler).CurrentConfig()
+	retention, retentionRule := ResolveRetention(config, kubernetesObject,
+		getNamespaceAnnotations(ctx, c, kubernetesObject.GetNamespace()), policy)
+	keepUntil := metav1.NewTime(utils.Now().Add(retention).Time)
+	trLabels := map[string]string{
+		moxv1alpha2.ActionLabel: string(ActionFromType(actionType)),
+		moxv1alpha2.OriginalHashLabel: OriginalHash(kubernetesObject.GetObjectKind().GroupVersionKind().Kind,
+			kubernetesObject.GetNamespace(), kubernetesObject.GetName()),
+	}
+	if policy != nil {
+		trLabels[moxv1alpha1.PolicyLabel] = policy.Name
+	}
+	if options.Reason != "" {
+		trLabels[moxv1alpha2.CaptureReasonLabel] = options.Reason
+	}
+	if kubernetesObject.GetNamespace() != "" {
+		trLabels[moxv1alpha2.OriginalNamespaceLabel] = kubernetesObject.GetNamespace()
 	}
 	dateTime := utils.Now().Format("20060102-150405")
 	setName := fmt.Sprintf("trashed-%s-%s-%s-%s", actionType, strings.ToLower(kubernetesObject.GetObjectKind().GroupVersionKind().Kind), kubernetesObject.GetName(), dateTime)
+	trashNamespace := config.TrashNamespace(kubernetesObject.GetNamespace())
+	if trashNamespace != kubernetesObject.GetNamespace() && kubernetesObject.GetNamespace() != "" {
+		// No vault os objetos de namespaces diferentes com o mesmo nome não podem ter o mesmo nome
+		setName = fmt.Sprintf("trashed-%s-%s-%s-%s-%s", actionType,
+			strings.ToLower(kubernetesObject.GetObjectKind().GroupVersionKind().Kind), kubernetesObject.GetNamespace(),
+			kubernetesObject.GetName(), dateTime)
+	}
+	// O nome é conhecido antes da criação, a chave no storageBackend e o Secret de dados usam o nome
+	setName = boundedName(setName)
 
 	// Cria o TrashedResource
-	trashed := &moxv1alpha1.TrashedResource{
+	trashed := &moxv1alpha2.TrashedResource{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TrashedResource",
-			APIVersion: "mox.app.br/v1alpha1",
+			APIVersion: moxv1alpha2.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: setName,
-			Name:         setName,
-			Namespace:    kubernetesObject.GetNamespace(),
-			Annotations:  map[string]string{"OriginalName": kubernetesObject.GetName()},
+			Name:      setName,
+			Namespace: trashNamespace,
+			Annotations: map[string]string{
+				"OriginalName":                kubernetesObject.GetName(),
+				utils.RetentionRuleAnnotation: retentionRule,
+			},
+			Labels: trLabels,
 		},
-		Spec: moxv1alpha1.TrashedResourceSpec{
+		Spec: moxv1alpha2.TrashedResourceSpec{
 			Data:      string(objectYAML),
-			KeepUntil: utils.GetTimetoKeepFromConfigMap((*utils.TRReconciler)(resourceReconciler)),
+			KeepUntil: keepUntil,
+			Original: moxv1alpha2.OriginalReference{
+				APIVersion: kubernetesObject.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+				Kind:       kubernetesObject.GetObjectKind().GroupVersionKind().Kind,
+				Name:       kubernetesObject.GetName(),
+				Namespace:  kubernetesObject.GetNamespace(),
+				UID:        kubernetesObject.GetUID(),
+			},
+			Action:      ActionFromType(actionType),
+			RequestedBy: options.RequestedBy,
 		},
 	}
+	trashed.Spec.Encoding = dataEncoding(config, len(objectYAML))
+	if err := protectData(ctx, c, config, trashed, kubernetesObject.GetObjectKind().GroupVersionKind().Kind, objectYAML,
+		options.Redacted); err != nil {
+		logger.Error(err, "Error on protect the data of TrashedResource")
+		captureFailed(recorder, kubernetesObject, kind, action, metrics.CaptureErrorProtect, err)
+		return err
+	}
+	if err := storeData(ctx, config, trashed, len(objectYAML)); err != nil {
+		logger.Error(err, "Error on store the data of TrashedResource", "name", trashed.Name, "namespace", trashed.Namespace)
+		captureFailed(recorder, kubernetesObject, kind, action, metrics.CaptureErrorStore, err)
+		return err
+	}
 	if err := trInteractor.Create(ctx, trashed); err != nil {
-		logger.Error(err, "Error on create TrashedResource")
-		return false
+		logger.Error(err, "Error on create TrashedResource", "size", trashed.Spec.Size.Stored)
+		if err := deleteExternalData(ctx, config, trashed); err != nil {
+			logger.Error(err, "Error on delete the stored data of TrashedResource", "key", trashed.Spec.External.Key)
+		}
+		captureFailed(recorder, kubernetesObject, kind, action, metrics.CaptureErrorCreate, err)
+		return err
 	}
+	if trashed.Spec.DataProtection == moxv1alpha2.DataSecret {
+		if err := createDataSecret(ctx, c, trashed, objectYAML); err != nil {
+			// O spec.data já está sem os valores sensíveis, o TrashedResource é mantido assim.
+			logger.Error(err, "Error on create the data Secret of TrashedResource, keeping it redacted",
+				"name", trashed.Name, "namespace", trashed.Namespace)
+			trashed.Spec.DataProtection = moxv1alpha2.DataRedacted
+			trashed.Spec.DataSecretRef = nil
+			if err := trInteractor.Update(ctx, trashed); err != nil {
+				logger.Error(err, "Error on update TrashedResource")
+			}
+		}
+	}
+	metrics.ObserveCapture(kind, trashed)
+	RecordEvent(recorder, trashed, kubernetesObject, corev1.EventTypeNormal, EventReasonTrashed, "Capture",
+		"Captured the %s %s %s, kept until %s", action, kind, objectName(kubernetesObject),
+		trashed.Spec.KeepUntil.UTC().Format(time.RFC3339))
 	logger.Info("Success on create TrashedResource",
 		"kubernetes_object", kubernetesObject.GetObjectKind().GroupVersionKind().Kind,
 		"actionType", actionType,
 		"name", trashed.Name,
 		"namespace", trashed.Namespace,
+		"policy", trLabels[moxv1alpha1.PolicyLabel],
+		"retentionRule", retentionRule,
+		"dataProtection", trashed.Spec.DataProtection,
+		"encoding", trashed.Spec.Encoding,
+		"size", trashed.Spec.Size.Stored,
+		"external", trashed.Spec.External != nil,
+		"requestedBy", options.RequestedBy,
+		"reason", options.Reason,
 	)
-	return true
+	return nil
 }
 
-func GetToReconcile(ctx context.Context, c client.Client, name string, namespace string) (*moxv1alpha1.TrashedResource, error) {
+func GetToReconcile(ctx context.Context, c client.Client, name string, namespace string) (*moxv1alpha2.TrashedResource, error) {
 	trInteractor := NewTrashedResourceInteractor(c)
 	trashedResource, err := trInteractor.Get(ctx, name, namespace)
 	if err != nil {
@@ -104,8 +230,8 @@ func NewTrashedResourceInteractor(c client.Client) TrashedResourceInteractor {
 }
 
 // Get recupera um TrashedResource pelo nome e namespace.
-func (interactor *trashedResourceInteractor) Get(ctx context.Context, name, namespace string) (*moxv1alpha1.TrashedResource, error) {
-	resource := &moxv1alpha1.TrashedResource{}
+func (interactor *trashedResourceInteractor) Get(ctx context.Context, name, namespace string) (*moxv1alpha2.TrashedResource, error) {
+	resource := &moxv1alpha2.TrashedResource{}
 	err := interactor.client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, resource)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -118,8 +244,8 @@ func (interactor *trashedResourceInteractor) Get(ctx context.Context, name, name
 
 // List recupera todos os TrashedResources em um determinado namespace.
 // Se o namespace for uma string vazia, lista os recursos de todos os namespaces.
-func (interactor *trashedResourceInteractor) List(ctx context.Context, namespace string) (*moxv1alpha1.TrashedResourceList, error) {
-	list := &moxv1alpha1.TrashedResourceList{}
+func (interactor *trashedResourceInteractor) List(ctx context.Context, namespace string) (*moxv1alpha2.TrashedResourceList, error) {
+	list := &moxv1alpha2.TrashedResourceList{}
 	opts := []client.ListOption{}
 
 	if namespace != "" && namespace != "all" {
@@ -133,18 +259,18 @@ func (interactor *trashedResourceInteractor) List(ctx context.Context, namespace
 }
 
 // Create cria um novo TrashedResource.
-func (interactor *trashedResourceInteractor) Create(ctx context.Context, resource *moxv1alpha1.TrashedResource) error {
+func (interactor *trashedResourceInteractor) Create(ctx context.Context, resource *moxv1alpha2.TrashedResource) error {
 	return interactor.client.Create(ctx, resource)
 }
 
 // Update atualiza um TrashedResource existente.
-func (interactor *trashedResourceInteractor) Update(ctx context.Context, resource *moxv1alpha1.TrashedResource) error {
+func (interactor *trashedResourceInteractor) Update(ctx context.Context, resource *moxv1alpha2.TrashedResource) error {
 	return interactor.client.Update(ctx, resource)
 }
 
 // Delete deleta um TrashedResource pelo nome e namespace.
 func (interactor *trashedResourceInteractor) Delete(ctx context.Context, name, namespace string) error {
-	resource := &moxv1alpha1.TrashedResource{
+	resource := &moxv1alpha2.TrashedResource{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
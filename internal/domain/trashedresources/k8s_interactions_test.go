@@ -2,18 +2,32 @@ package trashedresources
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+// newReconciler retorna um reconciler com a configuração já carregada, como após a leitura do ConfigMap.
+func newReconciler(config utils.WatchConfig) *TRReconciler {
+	reconciler := &TRReconciler{}
+	(*utils.TrashedResourceReconciler)(reconciler).UpdateConfig(func(current *utils.WatchConfig) {
+		*current = config
+	})
+	return reconciler
+}
+
 func TestNewTrashedResourceInteractor(t *testing.T) {
 	g := NewWithT(t)
 	c := fake.NewClientBuilder().Build()
@@ -25,8 +39,9 @@ func TestGetToReconcile(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
 	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
 
-	tr := &moxv1alpha1.TrashedResource{
+	tr := &moxv1alpha2.TrashedResource{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-tr",
 			Namespace: "default",
@@ -52,8 +67,9 @@ func TestDeleteToReconcile(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
 	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
 
-	tr := &moxv1alpha1.TrashedResource{
+	tr := &moxv1alpha2.TrashedResource{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-tr",
 			Namespace: "default",
@@ -68,7 +84,7 @@ func TestDeleteToReconcile(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 
 	// Verify deletion
-	err = c.Get(ctx, types.NamespacedName{Name: "test-tr", Namespace: "default"}, &moxv1alpha1.TrashedResource{})
+	err = c.Get(ctx, types.NamespacedName{Name: "test-tr", Namespace: "default"}, &moxv1alpha2.TrashedResource{})
 	g.Expect(err).To(HaveOccurred())
 
 	// Test delete non-existent
@@ -80,6 +96,7 @@ func TestCreateOrUpdatedManifest(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
 	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
 
 	c := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -95,17 +112,110 @@ func TestCreateOrUpdatedManifest(t *testing.T) {
 		},
 	}
 
-	reconciler := &TRReconciler{
+	reconciler := newReconciler(utils.WatchConfig{
 		MinutesToKeep: "60",
-	}
+	})
 
-	success := CreateOrUpdatedManifest(c, pod, reconciler, "deleted")
+	success := CreateOrUpdatedManifest(c, pod, reconciler, "deleted", nil)
 	g.Expect(success).To(BeTrue())
 
-	list := &moxv1alpha1.TrashedResourceList{}
+	list := &moxv1alpha2.TrashedResourceList{}
 	err := c.List(context.Background(), list)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(list.Items).To(HaveLen(1))
 	g.Expect(list.Items[0].Namespace).To(Equal("default"))
 	g.Expect(list.Items[0].Name).To(ContainSubstring("trashed-deleted-pod-test-pod-"))
+	g.Expect(list.Items[0].Annotations).To(HaveKeyWithValue(utils.RetentionRuleAnnotation, RetentionRuleDefault))
+	g.Expect(list.Items[0].Labels).To(HaveKeyWithValue(moxv1alpha2.ActionLabel, string(moxv1alpha2.ActionDelete)))
+	g.Expect(list.Items[0].Labels).To(HaveKeyWithValue(moxv1alpha2.OriginalHashLabel, OriginalHash("Pod", "default", "test-pod")))
+	g.Expect(list.Items[0].Labels).To(HaveKeyWithValue(moxv1alpha2.OriginalNamespaceLabel, "default"))
+	g.Expect(list.Items[0].Spec.Action).To(Equal(moxv1alpha2.ActionDelete))
+	g.Expect(list.Items[0].Spec.Original).To(Equal(moxv1alpha2.OriginalReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       "test-pod",
+		Namespace:  "default",
+	}))
+}
+
+func TestCaptureManifest_VaultNamespace(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{MinutesToKeep: "60", VaultNamespace: "trash-vault"})
+
+	for _, namespace := range []string{"team-a", "team-b"} {
+		pod := &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: namespace, UID: types.UID(namespace)},
+		}
+		g.Expect(CaptureManifest(c, pod, reconciler, "deleted", CaptureOptions{})).To(Succeed())
+		captured, err := IsDeletionCaptured(context.Background(), c, pod)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(captured).To(BeTrue())
+	}
+
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(2))
+	for _, trashed := range list.Items {
+		g.Expect(trashed.Namespace).To(Equal("trash-vault"))
+		g.Expect(trashed.Name).To(HavePrefix("trashed-deleted-pod-" + trashed.Spec.Original.Namespace + "-api-"))
+		g.Expect(trashed.Labels).To(HaveKeyWithValue(moxv1alpha2.OriginalNamespaceLabel, trashed.Spec.Original.Namespace))
+		g.Expect(trashed.Annotations).To(HaveKeyWithValue("OriginalName", "api"))
+	}
+}
+
+func TestCaptureManifest_VaultNamespaceLongName(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{MinutesToKeep: "60", VaultNamespace: "trash-vault"})
+
+	// Os nomes de namespaces têm até 63 caracteres e os de objetos até 253
+	namespace := strings.Repeat("n", 63)
+	for _, name := range []string{strings.Repeat("a", 253), strings.Repeat("a", 252) + "b"} {
+		configMap := &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		}
+		g.Expect(CaptureManifest(c, configMap, reconciler, "deleted", CaptureOptions{})).To(Succeed())
+	}
+
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(2))
+	g.Expect(list.Items[0].Name).NotTo(Equal(list.Items[1].Name))
+	for _, trashed := range list.Items {
+		g.Expect(validation.IsDNS1123Subdomain(trashed.Name)).To(BeEmpty())
+		g.Expect(trashed.GenerateName).To(BeEmpty())
+		g.Expect(trashed.Name).To(HavePrefix("trashed-deleted-configmap-" + namespace + "-aaa"))
+	}
+}
+
+func TestCreateOrUpdatedManifest_NamespaceRetentionAnnotation(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "audit",
+			Annotations: map[string]string{utils.RetentionAnnotation: "30d"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace).Build()
+
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep: "60",
+	})
+
+	success := CreateOrUpdatedManifest(c, newSecret("audit", nil), reconciler, "deleted", nil)
+	g.Expect(success).To(BeTrue())
+
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	g.Expect(list.Items[0].Annotations).To(HaveKeyWithValue(utils.RetentionRuleAnnotation, RetentionRuleNamespaceAnnotation))
+	g.Expect(utils.GetTimeRemaining(list.Items[0].Spec.KeepUntil.Time)).To(BeNumerically(">", 29*24*time.Hour))
 }
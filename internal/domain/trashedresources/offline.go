@@ -0,0 +1,132 @@
+package trashedresources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OfflineIndex é o último estado conhecido dos objetos de um kind que seriam capturados ao serem deletados.
+type OfflineIndex struct {
+	TakenAt metav1.Time      `json:"takenAt"`
+	Objects []map[string]any `json:"objects"`
+	// Redacted indica que os valores sensíveis dos objetos foram removidos, o kind tem proteção de dados.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// OfflineIndexKey retorna a chave do índice do kind no offlineIndex, eg. offline-index/apps/v1/deployment.json.
+func OfflineIndexKey(gvk schema.GroupVersionKind) string {
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	return fmt.Sprintf("offline-index/%s/%s/%s.json", group, gvk.Version, strings.ToLower(gvk.Kind))
+}
+
+// WriteOfflineIndex grava os objetos do kind no offlineIndex, sem managedFields e comprimidos como o
+// spec.data com encoding gzip. Os objetos dos kinds com proteção de dados em dataProtectionByKind são
+// gravados sem os valores sensíveis, como no spec.data.
+func WriteOfflineIndex(ctx context.Context, backend storage.Backend, config utils.WatchConfig,
+	gvk schema.GroupVersionKind, objects []unstructured.Unstructured) error {
+	index := OfflineIndex{
+		TakenAt:  metav1.NewTime(utils.Now().Time),
+		Objects:  []map[string]any{},
+		Redacted: config.DataProtectionOf(gvk.Kind) != utils.DataProtectionNone,
+	}
+	for _, object := range objects {
+		indexed := object.DeepCopy()
+		unstructured.RemoveNestedField(indexed.Object, "metadata", "managedFields")
+		if index.Redacted {
+			redactObject(indexed.Object)
+		}
+		index.Objects = append(index.Objects, indexed.Object)
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	encoded, err := EncodeData(data, moxv1alpha2.DataEncodingGzip)
+	if err != nil {
+		return err
+	}
+	return backend.Put(ctx, OfflineIndexKey(gvk), []byte(encoded))
+}
+
+// ReadOfflineIndex lê o índice do kind gravado por WriteOfflineIndex. Retorna nil quando ele não existe.
+func ReadOfflineIndex(ctx context.Context, backend storage.Backend, gvk schema.GroupVersionKind) (*OfflineIndex, error) {
+	encoded, err := backend.Get(ctx, OfflineIndexKey(gvk))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := DecodeData(string(encoded), moxv1alpha2.DataEncodingGzip)
+	if err != nil {
+		return nil, err
+	}
+	index := &OfflineIndex{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to decode the offline index of %s: %w", gvk.Kind, err)
+	}
+	return index, nil
+}
+
+// MissingObjects retorna os objetos do índice que não estão entre os objetos do cluster. Um objeto recriado
+// com o mesmo nome tem outro UID, o objeto do índice foi deletado.
+func MissingObjects(index *OfflineIndex, live []unstructured.Unstructured) []*unstructured.Unstructured {
+	if index == nil {
+		return nil
+	}
+	liveKeys := map[string]bool{}
+	for _, object := range live {
+		liveKeys[objectKey(&object)] = true
+	}
+	missing := []*unstructured.Unstructured{}
+	for _, indexed := range index.Objects {
+		object := &unstructured.Unstructured{Object: indexed}
+		if !liveKeys[objectKey(object)] {
+			missing = append(missing, object)
+		}
+	}
+	return missing
+}
+
+func objectKey(object *unstructured.Unstructured) string {
+	if object.GetUID() != "" {
+		return string(object.GetUID())
+	}
+	return object.GetNamespace() + "/" + object.GetName()
+}
+
+// IsDeletionCaptured verifica se a deleção do objeto, identificado pelo UID, já tem um TrashedResource. Ele é
+// procurado em todos os namespaces, pois pode estar no namespace do objeto ou no vaultNamespace.
+func IsDeletionCaptured(ctx context.Context, c client.Client, object client.Object) (bool, error) {
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	list := &moxv1alpha2.TrashedResourceList{}
+	if err := c.List(ctx, list, client.MatchingLabels{
+		moxv1alpha2.OriginalHashLabel: OriginalHash(kind, object.GetNamespace(), object.GetName()),
+	}); err != nil {
+		return false, err
+	}
+	for _, trashed := range list.Items {
+		if ActionOf(&trashed) != moxv1alpha2.ActionDelete {
+			continue
+		}
+		if original, err := OriginalOf(&trashed); err == nil && original.UID == object.GetUID() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
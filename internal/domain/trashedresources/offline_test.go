@@ -0,0 +1,117 @@
+package trashedresources
+
+import (
+	"context"
+	"testing"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newIndexedConfigMap(name string, uid types.UID) unstructured.Unstructured {
+	object := unstructured.Unstructured{}
+	object.SetAPIVersion("v1")
+	object.SetKind("ConfigMap")
+	object.SetName(name)
+	object.SetNamespace("default")
+	object.SetUID(uid)
+	_ = unstructured.SetNestedField(object.Object, "value", "data", "key")
+	return object
+}
+
+func TestOfflineIndex(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	backend := storage.NewFilesystem(t.TempDir())
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	g.Expect(OfflineIndexKey(gvk)).To(Equal("offline-index/core/v1/configmap.json"))
+	g.Expect(OfflineIndexKey(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})).
+		To(Equal("offline-index/apps/v1/deployment.json"))
+
+	// Sem índice não há objetos faltando
+	index, err := ReadOfflineIndex(ctx, backend, gvk)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(index).To(BeNil())
+	g.Expect(MissingObjects(index, nil)).To(BeEmpty())
+
+	kept, deleted, recreated := newIndexedConfigMap("kept", "1"), newIndexedConfigMap("deleted", "2"),
+		newIndexedConfigMap("recreated", "3")
+	g.Expect(WriteOfflineIndex(ctx, backend, utils.WatchConfig{}, gvk,
+		[]unstructured.Unstructured{kept, deleted, recreated})).To(Succeed())
+
+	index, err = ReadOfflineIndex(ctx, backend, gvk)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(index.TakenAt.IsZero()).To(BeFalse())
+	g.Expect(index.Objects).To(HaveLen(3))
+
+	missing := MissingObjects(index, []unstructured.Unstructured{kept, newIndexedConfigMap("recreated", "4")})
+	g.Expect(missing).To(HaveLen(2))
+	g.Expect(missing[0].GetName()).To(Equal("deleted"))
+	g.Expect(missing[0].Object["data"]).To(Equal(map[string]any{"key": "value"}))
+	g.Expect(missing[1].GetName()).To(Equal("recreated"))
+}
+
+func TestOfflineIndex_DataProtection(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	backend := storage.NewFilesystem(t.TempDir())
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	config := utils.WatchConfig{DataProtectionByKind: map[string]string{"configmap": utils.DataProtectionEncrypt}}
+
+	g.Expect(WriteOfflineIndex(ctx, backend, config, gvk,
+		[]unstructured.Unstructured{newIndexedConfigMap("app", "1")})).To(Succeed())
+	stored, err := backend.Get(ctx, OfflineIndexKey(gvk))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(stored)).NotTo(ContainSubstring("value"))
+
+	index, err := ReadOfflineIndex(ctx, backend, gvk)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(index.Redacted).To(BeTrue())
+	g.Expect(index.Objects[0]["data"]).To(Equal(map[string]any{"key": RedactedValue}))
+
+	// O objeto sem os valores sensíveis é capturado apenas assim, ele não pode ser restaurado
+	c := newProtectionClient()
+	missing := MissingObjects(index, nil)
+	g.Expect(CaptureManifest(c, missing[0], newReconciler(utils.WatchConfig{MinutesToKeep: "60"}), "deleted",
+		CaptureOptions{Reason: moxv1alpha2.CaptureReasonDetectedOffline, Redacted: index.Redacted})).To(Succeed())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(ctx, list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	g.Expect(list.Items[0].Spec.DataProtection).To(Equal(moxv1alpha2.DataRedacted))
+	g.Expect(list.Items[0].Spec.Encrypted).To(BeNil())
+}
+
+func TestIsDeletionCaptured(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := newProtectionClient()
+	object := newIndexedConfigMap("app", "1")
+
+	captured, err := IsDeletionCaptured(ctx, c, &object)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(captured).To(BeFalse())
+
+	g.Expect(CaptureManifest(c, &object, newReconciler(utils.WatchConfig{MinutesToKeep: "60"}), "deleted",
+		CaptureOptions{Reason: moxv1alpha2.CaptureReasonDetectedOffline})).To(Succeed())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(ctx, list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	g.Expect(list.Items[0].Labels).To(HaveKeyWithValue(moxv1alpha2.CaptureReasonLabel,
+		moxv1alpha2.CaptureReasonDetectedOffline))
+
+	captured, err = IsDeletionCaptured(ctx, c, &object)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(captured).To(BeTrue())
+
+	// O mesmo nome com outro UID é outro objeto
+	recreated := newIndexedConfigMap("app", "2")
+	captured, err = IsDeletionCaptured(ctx, c, &recreated)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(captured).To(BeFalse())
+}
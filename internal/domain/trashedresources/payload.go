@@ -0,0 +1,252 @@
+package trashedresources
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"slices"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EncodeData codifica o objeto conforme encoding, sem alterá-lo quando encoding é vazio.
+func EncodeData(data []byte, encoding moxv1alpha2.DataEncoding) (string, error) {
+	switch encoding {
+	case "":
+		return string(data), nil
+	case moxv1alpha2.DataEncodingGzip:
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(data); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(compressed.Bytes()), nil
+	}
+	return "", fmt.Errorf("unknown data encoding %q", encoding)
+}
+
+// DecodeData decodifica o objeto codificado por EncodeData.
+func DecodeData(data string, encoding moxv1alpha2.DataEncoding) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(data), nil
+	case moxv1alpha2.DataEncodingGzip:
+		compressed, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the data: %w", err)
+		}
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress the data: %w", err)
+		}
+		defer func() { _ = reader.Close() }()
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress the data: %w", err)
+		}
+		return decompressed, nil
+	}
+	return nil, fmt.Errorf("unknown data encoding %q", encoding)
+}
+
+// dataEncoding retorna a codificação dos objetos capturados com o tamanho size: comprimidos acima de compressAbove.
+func dataEncoding(config utils.WatchConfig, size int) moxv1alpha2.DataEncoding {
+	if int64(size) > config.CompressionThreshold() {
+		return moxv1alpha2.DataEncodingGzip
+	}
+	return ""
+}
+
+// storeData codifica o spec.data com spec.encoding e registra o tamanho do objeto, somado ao do objeto
+// criptografado de spec.encrypted. Quando eles ainda passam de maxInlineSize são guardados no storageBackend,
+// que é obrigatório nesse caso.
+func storeData(ctx context.Context, config utils.WatchConfig, trashed *moxv1alpha2.TrashedResource, originalSize int) error {
+	data, err := EncodeData([]byte(trashed.Spec.Data), trashed.Spec.Encoding)
+	if err != nil {
+		return err
+	}
+	trashed.Spec.Data = data
+	stored := len(data)
+	if trashed.Spec.Encrypted != nil {
+		stored += len(trashed.Spec.Encrypted.Key) + len(trashed.Spec.Encrypted.Data)
+	}
+	trashed.Spec.Size = &moxv1alpha2.DataSize{Original: int64(originalSize), Stored: int64(stored)}
+	if int64(stored) <= config.InlineLimit() {
+		return nil
+	}
+
+	if config.StorageBackend == "" {
+		return fmt.Errorf("the object has %d bytes once encoded, more than maxInlineSize (%d), "+
+			"and no storageBackend is configured", stored, config.InlineLimit())
+	}
+	backend, err := storage.Open(config.StorageBackend)
+	if err != nil {
+		return err
+	}
+	key, encryptedKey := ExternalKeys(trashed)
+	external := &moxv1alpha2.ExternalData{URL: config.StorageBackend, Key: key}
+	if err := backend.Put(ctx, external.Key, []byte(data)); err != nil {
+		return fmt.Errorf("failed to store the object in %s: %w", config.StorageBackend, err)
+	}
+	if trashed.Spec.Encrypted != nil {
+		external.EncryptedKey = encryptedKey
+		if err := backend.Put(ctx, external.EncryptedKey, []byte(trashed.Spec.Encrypted.Data)); err != nil {
+			_ = backend.Delete(ctx, external.Key)
+			return fmt.Errorf("failed to store the encrypted object in %s: %w", config.StorageBackend, err)
+		}
+		trashed.Spec.Encrypted.Data = ""
+	}
+	trashed.Spec.Data = ""
+	trashed.Spec.External = external
+	trashed.Finalizers = append(trashed.Finalizers, moxv1alpha2.ExternalDataFinalizer)
+	return nil
+}
+
+// InlineData retorna o objeto de spec.data decodificado. Retorna erro quando ele está no storageBackend.
+func InlineData(trashed *moxv1alpha2.TrashedResource) ([]byte, error) {
+	if trashed.Spec.External != nil {
+		return nil, fmt.Errorf("the data of TrashedResource %s/%s is stored in %s", trashed.Namespace, trashed.Name,
+			trashed.Spec.External.URL)
+	}
+	return DecodeData(trashed.Spec.Data, trashed.Spec.Encoding)
+}
+
+// TrashedData retorna o objeto de spec.data decodificado, lido do storageBackend quando está nele. Quando não
+// é vazio, storageURL substitui a URL do storageBackend gravada no TrashedResource. É usada pelo plugin, com
+// as permissões do usuário; o controller lê o storageBackend configurado com backendData.
+func TrashedData(ctx context.Context, trashed *moxv1alpha2.TrashedResource, storageURL string) ([]byte, error) {
+	data, err := storedData(ctx, trashed, storageURL)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeData(data, trashed.Spec.Encoding)
+}
+
+// storedData retorna o spec.data ainda codificado com spec.encoding, lido do storageBackend quando está nele.
+func storedData(ctx context.Context, trashed *moxv1alpha2.TrashedResource, storageURL string) (string, error) {
+	external := trashed.Spec.External
+	if external == nil {
+		return trashed.Spec.Data, nil
+	}
+	return readExternal(ctx, trashed, storageURL, external.Key)
+}
+
+// storedEncrypted retorna o spec.encrypted com o objeto criptografado, lido do storageBackend quando está nele.
+func storedEncrypted(ctx context.Context, trashed *moxv1alpha2.TrashedResource,
+	storageURL string) (*moxv1alpha2.EncryptedData, error) {
+	if trashed.Spec.Encrypted == nil {
+		return nil, fmt.Errorf("TrashedResource %s/%s has no encrypted data", trashed.Namespace, trashed.Name)
+	}
+	encrypted := trashed.Spec.Encrypted.DeepCopy()
+	if trashed.Spec.External == nil || trashed.Spec.External.EncryptedKey == "" {
+		return encrypted, nil
+	}
+	data, err := readExternal(ctx, trashed, storageURL, trashed.Spec.External.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted.Data = data
+	return encrypted, nil
+}
+
+// readExternal lê a chave do storageBackend do TrashedResource, ou de storageURL quando não é vazio.
+func readExternal(ctx context.Context, trashed *moxv1alpha2.TrashedResource, storageURL, key string) (string, error) {
+	if storageURL == "" {
+		storageURL = trashed.Spec.External.URL
+	}
+	backend, err := storage.Open(storageURL)
+	if err != nil {
+		return "", err
+	}
+	data, err := backend.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the data of TrashedResource %s/%s from %s: %w",
+			trashed.Namespace, trashed.Name, storageURL, err)
+	}
+	return string(data), nil
+}
+
+// ExternalKeys retorna as chaves do objeto e do objeto criptografado do TrashedResource no storageBackend,
+// derivadas do namespace e do nome.
+func ExternalKeys(trashed *moxv1alpha2.TrashedResource) (string, string) {
+	key := trashed.Namespace + "/" + trashed.Name
+	return key, key + ".encrypted"
+}
+
+// openStorageBackend abre o storageBackend configurado. O controller só usa ele e as chaves de ExternalKeys,
+// nunca a URL e as chaves do spec.external, que são escritas pelo usuário.
+func openStorageBackend(config utils.WatchConfig) (storage.Backend, error) {
+	if config.StorageBackend == "" {
+		return nil, fmt.Errorf("no storageBackend is configured")
+	}
+	return storage.Open(config.StorageBackend)
+}
+
+// backendData retorna o spec.data ainda codificado e o objeto criptografado do TrashedResource, lidos do
+// storageBackend configurado. O objeto criptografado é vazio quando não há spec.encrypted.
+func backendData(ctx context.Context, config utils.WatchConfig, trashed *moxv1alpha2.TrashedResource) (string,
+	string, error) {
+	backend, err := openStorageBackend(config)
+	if err != nil {
+		return "", "", err
+	}
+	key, encryptedKey := ExternalKeys(trashed)
+	data, err := backend.Get(ctx, key)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the data of TrashedResource %s/%s from %s: %w",
+			trashed.Namespace, trashed.Name, config.StorageBackend, err)
+	}
+	if trashed.Spec.Encrypted == nil {
+		return string(data), "", nil
+	}
+	encrypted, err := backend.Get(ctx, encryptedKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the encrypted data of TrashedResource %s/%s from %s: %w",
+			trashed.Namespace, trashed.Name, config.StorageBackend, err)
+	}
+	return string(data), string(encrypted), nil
+}
+
+// ReleaseExternalData apaga do storageBackend o objeto do TrashedResource que está sendo deletado e remove
+// o finalizer mox.app.br/external-data.
+func ReleaseExternalData(ctx context.Context, c client.Client, config utils.WatchConfig,
+	trashed *moxv1alpha2.TrashedResource) error {
+	if !slices.Contains(trashed.Finalizers, moxv1alpha2.ExternalDataFinalizer) {
+		return nil
+	}
+	if err := deleteExternalData(ctx, config, trashed); err != nil {
+		return err
+	}
+	trashed.Finalizers = slices.DeleteFunc(trashed.Finalizers, func(finalizer string) bool {
+		return finalizer == moxv1alpha2.ExternalDataFinalizer
+	})
+	return c.Update(ctx, trashed)
+}
+
+// deleteExternalData apaga do storageBackend configurado o objeto e o objeto criptografado do TrashedResource.
+func deleteExternalData(ctx context.Context, config utils.WatchConfig, trashed *moxv1alpha2.TrashedResource) error {
+	if trashed.Spec.External == nil {
+		return nil
+	}
+	backend, err := openStorageBackend(config)
+	if err != nil {
+		return err
+	}
+	key, encryptedKey := ExternalKeys(trashed)
+	if trashed.Spec.Encrypted != nil {
+		if err := backend.Delete(ctx, encryptedKey); err != nil {
+			return err
+		}
+	}
+	return backend.Delete(ctx, key)
+}
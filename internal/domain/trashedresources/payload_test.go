@@ -0,0 +1,174 @@
+package trashedresources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/metrics"
+	"trashed-resources/internal/storage"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newLargeConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "large", Namespace: "default"},
+		Data:       map[string]string{"content": strings.Repeat("trashed resources ", 2048)},
+	}
+}
+
+func captureLargeConfigMap(g *WithT, c client.Client, reconciler *TRReconciler) moxv1alpha2.TrashedResource {
+	g.Expect(CreateOrUpdatedManifest(c, newLargeConfigMap(), reconciler, "deleted", nil)).To(BeTrue())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	return list.Items[0]
+}
+
+func TestEncodeData(t *testing.T) {
+	g := NewWithT(t)
+	manifest := []byte(strings.Repeat("data: value\n", 100))
+
+	encoded, err := EncodeData(manifest, moxv1alpha2.DataEncodingGzip)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(encoded)).To(BeNumerically("<", len(manifest)))
+	decoded, err := DecodeData(encoded, moxv1alpha2.DataEncodingGzip)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(decoded).To(Equal(manifest))
+
+	encoded, err = EncodeData(manifest, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(encoded).To(Equal(string(manifest)))
+
+	_, err = EncodeData(manifest, "zstd")
+	g.Expect(err).To(HaveOccurred())
+	_, err = DecodeData("not base64!", moxv1alpha2.DataEncodingGzip)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestCreateOrUpdatedManifest_Compressed(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+
+	trashed := captureLargeConfigMap(g, c, newReconciler(utils.WatchConfig{MinutesToKeep: "60", CompressAbove: 1024}))
+	g.Expect(trashed.Spec.Encoding).To(Equal(moxv1alpha2.DataEncodingGzip))
+	g.Expect(trashed.Spec.External).To(BeNil())
+	g.Expect(trashed.Spec.Size.Stored).To(BeNumerically("<", trashed.Spec.Size.Original))
+	g.Expect(trashed.Spec.Size.Stored).To(BeNumerically("==", len(trashed.Spec.Data)))
+
+	data, err := TrashedData(context.Background(), &trashed, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(int64(len(data))).To(Equal(trashed.Spec.Size.Original))
+	g.Expect(string(data)).To(ContainSubstring("name: large"))
+
+	original, err := OriginalOf(&trashed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(original.Name).To(Equal("large"))
+}
+
+func TestCreateOrUpdatedManifest_NotCompressed(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+
+	trashed := captureLargeConfigMap(g, c, newReconciler(utils.WatchConfig{MinutesToKeep: "60"}))
+	g.Expect(trashed.Spec.Encoding).To(BeEmpty())
+	g.Expect(trashed.Spec.Size.Stored).To(Equal(trashed.Spec.Size.Original))
+	g.Expect(trashed.Spec.Data).To(ContainSubstring("name: large"))
+}
+
+func TestCreateOrUpdatedManifest_External(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	root := t.TempDir()
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep:  "60",
+		CompressAbove:  1024,
+		MaxInlineSize:  64,
+		StorageBackend: "file://" + root,
+	})
+
+	trashed := captureLargeConfigMap(g, c, reconciler)
+	g.Expect(trashed.Spec.Data).To(BeEmpty())
+	g.Expect(trashed.Spec.External).To(Equal(&moxv1alpha2.ExternalData{
+		URL: "file://" + root,
+		Key: "default/" + trashed.Name,
+	}))
+	g.Expect(trashed.Finalizers).To(ContainElement(moxv1alpha2.ExternalDataFinalizer))
+	g.Expect(filepath.Join(root, "default", trashed.Name)).To(BeAnExistingFile())
+
+	data, err := TrashedData(ctx, &trashed, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring("name: large"))
+	manifest, err := RestorableManifest(ctx, c, &trashed, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(manifest).To(Equal(data))
+	_, err = InlineData(&trashed)
+	g.Expect(err).To(HaveOccurred())
+
+	// A storage URL replaces the one recorded in the TrashedResource
+	moved := t.TempDir()
+	g.Expect(os.Rename(filepath.Join(root, "default"), filepath.Join(moved, "default"))).To(Succeed())
+	_, err = TrashedData(ctx, &trashed, "")
+	g.Expect(err).To(HaveOccurred())
+	data, err = TrashedData(ctx, &trashed, "file://"+moved)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring("name: large"))
+	g.Expect(os.Rename(filepath.Join(moved, "default"), filepath.Join(root, "default"))).To(Succeed())
+
+	g.Expect(ReleaseExternalData(ctx, c, (*utils.TrashedResourceReconciler)(reconciler).CurrentConfig(), &trashed)).
+		To(Succeed())
+	g.Expect(trashed.Finalizers).NotTo(ContainElement(moxv1alpha2.ExternalDataFinalizer))
+	g.Expect(filepath.Join(root, "default", trashed.Name)).NotTo(BeAnExistingFile())
+	// Sem o finalizer não há nada a liberar
+	g.Expect(ReleaseExternalData(ctx, c, (*utils.TrashedResourceReconciler)(reconciler).CurrentConfig(), &trashed)).
+		To(Succeed())
+}
+
+func TestReleaseExternalData_ConfiguredBackend(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	root, other := t.TempDir(), t.TempDir()
+	g.Expect(storage.NewFilesystem(root).Put(ctx, "default/trashed-big", []byte("stored-data"))).To(Succeed())
+	g.Expect(storage.NewFilesystem(other).Put(ctx, "team-b/trashed-other", []byte("other-data"))).To(Succeed())
+	trashed := &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "trashed-big",
+			Namespace:  "default",
+			Finalizers: []string{moxv1alpha2.ExternalDataFinalizer},
+		},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			External: &moxv1alpha2.ExternalData{URL: "file://" + other, Key: "team-b/trashed-other"},
+		},
+	}
+	c := newProtectionClient(trashed)
+
+	// A URL e a chave do spec.external, escritas pelo usuário, não são usadas
+	g.Expect(ReleaseExternalData(ctx, c, utils.WatchConfig{StorageBackend: "file://" + root}, trashed)).To(Succeed())
+	g.Expect(filepath.Join(root, "default", "trashed-big")).NotTo(BeAnExistingFile())
+	g.Expect(filepath.Join(other, "team-b", "trashed-other")).To(BeAnExistingFile())
+}
+
+func TestCreateOrUpdatedManifest_ExternalWithoutBackend(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{MinutesToKeep: "60", MaxInlineSize: 64})
+	storeErrors := metrics.CaptureErrors.WithLabelValues("ConfigMap", "delete", metrics.CaptureErrorStore)
+	before := testutil.ToFloat64(storeErrors)
+
+	g.Expect(CreateOrUpdatedManifest(c, newLargeConfigMap(), reconciler, "deleted", nil)).To(BeFalse())
+	g.Expect(testutil.ToFloat64(storeErrors)).To(Equal(before + 1))
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(BeEmpty())
+}
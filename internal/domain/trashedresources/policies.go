@@ -0,0 +1,99 @@
+package trashedresources
+
+import (
+	"slices"
+	"strings"
+	"time"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+
+	utils "trashed-resources/internal/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MatchPolicy retorna a policy que captura o objeto para a ação informada, ou nil se nenhuma captura.
+// Quando várias policies capturam o objeto, vence a que o mantém por mais tempo (policies sem retention
+// usam a retention do ConfigMap, defaultRetention); empates são resolvidos pelo nome.
+func MatchPolicy(policies []moxv1alpha1.TrashedResourcePolicy, kubernetesObject client.Object,
+	action moxv1alpha1.TrashedAction, namespaceLabels map[string]string,
+	defaultRetention time.Duration) *moxv1alpha1.TrashedResourcePolicy {
+	var matched *moxv1alpha1.TrashedResourcePolicy
+	matchedRetention := time.Duration(0)
+
+	for i := range policies {
+		policy := &policies[i]
+		if !PolicyCaptures(policy, kubernetesObject, action, namespaceLabels) {
+			continue
+		}
+		retention := defaultRetention
+		if policy.Spec.Retention != nil {
+			retention = utils.GetDurationToKeep(*policy.Spec.Retention)
+		}
+		if matched == nil || retention > matchedRetention ||
+			(retention == matchedRetention && policy.Name < matched.Name) {
+			matched = policy
+			matchedRetention = retention
+		}
+	}
+
+	return matched
+}
+
+// PolicyCaptures verifica se a policy captura o objeto para a ação informada.
+func PolicyCaptures(policy *moxv1alpha1.TrashedResourcePolicy, kubernetesObject client.Object,
+	action moxv1alpha1.TrashedAction, namespaceLabels map[string]string) bool {
+	spec := policy.Spec
+	if !slices.Contains(spec.Actions, action) || !policyMatchesKind(spec.Kinds, kubernetesObject) {
+		return false
+	}
+
+	namespace := kubernetesObject.GetNamespace()
+	if slices.Contains(spec.ExcludedNamespaces, namespace) {
+		return false
+	}
+	if spec.NamespaceSelector != nil {
+		if namespace == "" || !selectorMatches(spec.NamespaceSelector, namespaceLabels) {
+			return false
+		}
+	}
+	if spec.LabelSelector != nil && !selectorMatches(spec.LabelSelector, kubernetesObject.GetLabels()) {
+		return false
+	}
+
+	return true
+}
+
+func policyMatchesKind(kinds []moxv1alpha1.PolicyKind, kubernetesObject client.Object) bool {
+	gvk := kubernetesObject.GetObjectKind().GroupVersionKind()
+	for _, kind := range kinds {
+		if !strings.EqualFold(kind.Kind, gvk.Kind) {
+			continue
+		}
+		if kind.Group != "" && kind.Group != gvk.Group {
+			continue
+		}
+		if kind.Version != "" && kind.Version != gvk.Version {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func selectorMatches(labelSelector *metav1.LabelSelector, objectLabels map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		logger.Error(err, "Invalid label selector in TrashedResourcePolicy")
+		return false
+	}
+	return selector.Matches(labels.Set(objectLabels))
+}
+
+// PoliciesUseNamespaceSelector verifica se alguma policy precisa dos labels do namespace do objeto.
+func PoliciesUseNamespaceSelector(policies []moxv1alpha1.TrashedResourcePolicy) bool {
+	return slices.ContainsFunc(policies, func(policy moxv1alpha1.TrashedResourcePolicy) bool {
+		return policy.Spec.NamespaceSelector != nil
+	})
+}
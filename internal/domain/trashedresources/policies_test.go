@@ -0,0 +1,137 @@
+package trashedresources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPolicy(name string, kinds []moxv1alpha1.PolicyKind, actions ...moxv1alpha1.TrashedAction) moxv1alpha1.TrashedResourcePolicy {
+	return moxv1alpha1.TrashedResourcePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: moxv1alpha1.TrashedResourcePolicySpec{
+			Kinds:   kinds,
+			Actions: actions,
+		},
+	}
+}
+
+func newSecret(namespace string, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: namespace, Labels: labels},
+	}
+}
+
+func TestPolicyCaptures(t *testing.T) {
+	g := NewWithT(t)
+	secretKind := []moxv1alpha1.PolicyKind{{Kind: "Secret"}}
+
+	policy := newPolicy("secrets", secretKind, moxv1alpha1.ActionDelete)
+	g.Expect(PolicyCaptures(&policy, newSecret("default", nil), moxv1alpha1.ActionDelete, nil)).To(BeTrue())
+	g.Expect(PolicyCaptures(&policy, newSecret("default", nil), moxv1alpha1.ActionUpdate, nil)).To(BeFalse())
+
+	deployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}}
+	g.Expect(PolicyCaptures(&policy, deployment, moxv1alpha1.ActionDelete, nil)).To(BeFalse())
+
+	t.Run("group and version", func(t *testing.T) {
+		withGroup := newPolicy("deployments", []moxv1alpha1.PolicyKind{{Group: "apps", Version: "v1", Kind: "deployment"}},
+			moxv1alpha1.ActionDelete)
+		g.Expect(PolicyCaptures(&withGroup, deployment, moxv1alpha1.ActionDelete, nil)).To(BeTrue())
+
+		withOtherGroup := newPolicy("deployments", []moxv1alpha1.PolicyKind{{Group: "extensions", Kind: "Deployment"}},
+			moxv1alpha1.ActionDelete)
+		g.Expect(PolicyCaptures(&withOtherGroup, deployment, moxv1alpha1.ActionDelete, nil)).To(BeFalse())
+	})
+
+	t.Run("excluded namespaces", func(t *testing.T) {
+		excluded := newPolicy("secrets", secretKind, moxv1alpha1.ActionDelete)
+		excluded.Spec.ExcludedNamespaces = []string{"kube-system"}
+		g.Expect(PolicyCaptures(&excluded, newSecret("kube-system", nil), moxv1alpha1.ActionDelete, nil)).To(BeFalse())
+		g.Expect(PolicyCaptures(&excluded, newSecret("default", nil), moxv1alpha1.ActionDelete, nil)).To(BeTrue())
+	})
+
+	t.Run("namespace selector", func(t *testing.T) {
+		selected := newPolicy("secrets", secretKind, moxv1alpha1.ActionDelete)
+		selected.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+		g.Expect(PolicyCaptures(&selected, newSecret("app", nil), moxv1alpha1.ActionDelete,
+			map[string]string{"env": "prod"})).To(BeTrue())
+		g.Expect(PolicyCaptures(&selected, newSecret("app", nil), moxv1alpha1.ActionDelete,
+			map[string]string{"env": "dev"})).To(BeFalse())
+		// Cluster scoped objects have no namespace to select
+		g.Expect(PolicyCaptures(&selected, newSecret("", nil), moxv1alpha1.ActionDelete, nil)).To(BeFalse())
+	})
+
+	t.Run("label selector", func(t *testing.T) {
+		labeled := newPolicy("secrets", secretKind, moxv1alpha1.ActionDelete)
+		labeled.Spec.LabelSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "backup", Operator: metav1.LabelSelectorOpExists},
+			},
+		}
+		g.Expect(PolicyCaptures(&labeled, newSecret("default", map[string]string{"backup": "yes"}),
+			moxv1alpha1.ActionDelete, nil)).To(BeTrue())
+		g.Expect(PolicyCaptures(&labeled, newSecret("default", nil), moxv1alpha1.ActionDelete, nil)).To(BeFalse())
+	})
+}
+
+func TestMatchPolicy(t *testing.T) {
+	g := NewWithT(t)
+	secretKind := []moxv1alpha1.PolicyKind{{Kind: "Secret"}}
+
+	short := newPolicy("a-short", secretKind, moxv1alpha1.ActionDelete)
+	short.Spec.Retention = &moxv1alpha1.Retention{Hours: 1}
+	long := newPolicy("b-long", secretKind, moxv1alpha1.ActionDelete)
+	long.Spec.Retention = &moxv1alpha1.Retention{Days: 30}
+	defaultRetention := newPolicy("c-default", secretKind, moxv1alpha1.ActionDelete)
+	updates := newPolicy("d-updates", secretKind, moxv1alpha1.ActionUpdate)
+
+	policies := []moxv1alpha1.TrashedResourcePolicy{short, long, defaultRetention, updates}
+
+	matched := MatchPolicy(policies, newSecret("default", nil), moxv1alpha1.ActionDelete, nil, time.Hour)
+	g.Expect(matched).NotTo(BeNil())
+	g.Expect(matched.Name).To(Equal("b-long"))
+
+	// Ties are resolved by name
+	matched = MatchPolicy([]moxv1alpha1.TrashedResourcePolicy{defaultRetention, short}, newSecret("default", nil),
+		moxv1alpha1.ActionDelete, nil, time.Hour)
+	g.Expect(matched.Name).To(Equal("a-short"))
+
+	matched = MatchPolicy(policies, newSecret("default", nil), moxv1alpha1.ActionUpdate, nil, time.Hour)
+	g.Expect(matched.Name).To(Equal("d-updates"))
+
+	g.Expect(MatchPolicy(nil, newSecret("default", nil), moxv1alpha1.ActionDelete, nil, time.Hour)).To(BeNil())
+}
+
+func TestCreateOrUpdatedManifest_WithPolicy(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	policy := newPolicy("audit-secrets", []moxv1alpha1.PolicyKind{{Kind: "Secret"}}, moxv1alpha1.ActionDelete)
+	policy.Spec.Retention = &moxv1alpha1.Retention{Days: 30}
+
+	success := CreateOrUpdatedManifest(c, newSecret("default", nil), newReconciler(utils.WatchConfig{MinutesToKeep: "60"}), "deleted", &policy)
+	g.Expect(success).To(BeTrue())
+
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	g.Expect(list.Items[0].Labels).To(HaveKeyWithValue(moxv1alpha1.PolicyLabel, "audit-secrets"))
+
+	g.Expect(time.Until(list.Items[0].Spec.KeepUntil.Time)).To(BeNumerically("~", 30*24*time.Hour, time.Minute))
+}
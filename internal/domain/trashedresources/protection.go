@@ -0,0 +1,279 @@
+package trashedresources
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	utils "trashed-resources/internal/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RedactedValue substitui os valores sensíveis do objeto capturado.
+	RedactedValue = "REDACTED"
+	// DataSecretKey é a chave do Secret do TrashedResource que guarda o objeto capturado.
+	DataSecretKey = "manifest"
+)
+
+// sensitiveFields são os campos cujos valores são removidos do spec.data quando há proteção de dados.
+var sensitiveFields = []string{"data", "stringData", "binaryData"}
+
+// embeddingAnnotations são as anotações que contêm uma cópia do objeto, com os seus valores sensíveis.
+var embeddingAnnotations = []string{corev1.LastAppliedConfigAnnotation}
+
+// protectData aplica ao TrashedResource a proteção de dados configurada para o kind do objeto. O spec.data
+// recebe o objeto com os valores sensíveis removidos e o objeto completo, codificado com spec.encoding, é
+// criptografado ou, no modo secret, guardado no Secret criado por createDataSecret. Quando não é possível
+// criptografar, o objeto fica apenas sem os valores sensíveis, que nunca são gravados em texto aberto.
+// O objeto que já está sem os valores sensíveis, alreadyRedacted, é guardado apenas assim.
+func protectData(ctx context.Context, c client.Client, config utils.WatchConfig, trashed *moxv1alpha2.TrashedResource,
+	kind string, manifest []byte, alreadyRedacted bool) error {
+	mode := config.DataProtectionOf(kind)
+	if alreadyRedacted {
+		mode = utils.DataProtectionRedact
+	}
+	if mode == utils.DataProtectionNone {
+		return nil
+	}
+
+	redacted, err := RedactManifest(manifest)
+	if err != nil {
+		return err
+	}
+	trashed.Spec.Data = string(redacted)
+	trashed.Spec.DataProtection = moxv1alpha2.DataRedacted
+
+	switch mode {
+	case utils.DataProtectionEncrypt:
+		encoded, err := EncodeData(manifest, trashed.Spec.Encoding)
+		if err != nil {
+			return err
+		}
+		encrypted, err := encryptWithKeyRef(ctx, c, encryptionKeyRef(config), []byte(encoded))
+		if err != nil {
+			logger.Error(err, "Failed to encrypt the captured object, storing it redacted", "kind", kind,
+				"name", trashed.Spec.Original.Name, "namespace", trashed.Namespace)
+			return nil
+		}
+		trashed.Spec.DataProtection = moxv1alpha2.DataEncrypted
+		trashed.Spec.Encrypted = encrypted
+	case utils.DataProtectionSecret:
+		trashed.Spec.DataProtection = moxv1alpha2.DataSecret
+		trashed.Spec.DataSecretRef = &moxv1alpha2.SecretKeyReference{Name: trashed.Name, Key: DataSecretKey}
+	}
+	return nil
+}
+
+// createDataSecret cria o Secret, com dono o TrashedResource, que guarda o objeto capturado no modo secret.
+func createDataSecret(ctx context.Context, c client.Client, trashed *moxv1alpha2.TrashedResource, manifest []byte) error {
+	encoded, err := EncodeData(manifest, trashed.Spec.Encoding)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      trashed.Spec.DataSecretRef.Name,
+			Namespace: trashed.Namespace,
+			Labels:    map[string]string{moxv1alpha2.DataSecretLabel: trashed.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(trashed, moxv1alpha2.GroupVersion.WithKind("TrashedResource")),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{trashed.Spec.DataSecretRef.Key: []byte(encoded)},
+	}
+	return c.Create(ctx, secret)
+}
+
+// IsDataSecret indica se o objeto é um Secret que guarda o objeto capturado de um TrashedResource. Esses
+// Secrets nunca são capturados.
+func IsDataSecret(kubernetesObject client.Object) bool {
+	_, found := kubernetesObject.GetLabels()[moxv1alpha2.DataSecretLabel]
+	return found
+}
+
+// RestorableManifest retorna o objeto capturado completo, como deve ser restaurado: descriptografado ou lido
+// do Secret do TrashedResource conforme spec.dataProtection, ou lido com TrashedData. Objetos com os valores
+// removidos não podem ser restaurados.
+func RestorableManifest(ctx context.Context, c client.Client, trashed *moxv1alpha2.TrashedResource,
+	storageURL string) ([]byte, error) {
+	var encoded []byte
+	var err error
+	switch trashed.Spec.DataProtection {
+	case moxv1alpha2.DataRedacted:
+		return nil, fmt.Errorf("the sensitive data of TrashedResource %s/%s was redacted, it can not be restored",
+			trashed.Namespace, trashed.Name)
+	case moxv1alpha2.DataEncrypted:
+		var encrypted *moxv1alpha2.EncryptedData
+		if encrypted, err = storedEncrypted(ctx, trashed, storageURL); err != nil {
+			return nil, err
+		}
+		var key []byte
+		if key, err = secretKeyValue(ctx, c, encrypted.KeyRef, trashed.Namespace); err != nil {
+			return nil, err
+		}
+		encoded, err = DecryptData(key, *encrypted)
+	case moxv1alpha2.DataSecret:
+		if trashed.Spec.DataSecretRef == nil {
+			return nil, fmt.Errorf("TrashedResource %s/%s has no data Secret", trashed.Namespace, trashed.Name)
+		}
+		encoded, err = secretKeyValue(ctx, c, *trashed.Spec.DataSecretRef, trashed.Namespace)
+	default:
+		return TrashedData(ctx, trashed, storageURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return DecodeData(string(encoded), trashed.Spec.Encoding)
+}
+
+// RedactManifest substitui por RedactedValue os valores de data, stringData e binaryData do objeto,
+// mantendo as chaves, e as anotações que contêm uma cópia do objeto, como a do kubectl apply.
+func RedactManifest(manifest []byte) ([]byte, error) {
+	object := map[string]any{}
+	if err := yaml.Unmarshal(manifest, &object); err != nil {
+		return nil, fmt.Errorf("failed to decode the captured object: %w", err)
+	}
+	redactObject(object)
+	return yaml.Marshal(object)
+}
+
+// redactObject substitui os valores sensíveis do objeto, como RedactManifest.
+func redactObject(object map[string]any) {
+	for _, field := range sensitiveFields {
+		values, ok := object[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key := range values {
+			values[key] = RedactedValue
+		}
+	}
+	metadata, _ := object["metadata"].(map[string]any)
+	if annotations, ok := metadata["annotations"].(map[string]any); ok {
+		for _, annotation := range embeddingAnnotations {
+			if _, found := annotations[annotation]; found {
+				annotations[annotation] = RedactedValue
+			}
+		}
+	}
+}
+
+// EncryptData criptografa o objeto com uma chave de dados aleatória (AES-GCM), que é criptografada com
+// a chave key, de 32 bytes.
+func EncryptData(key []byte, keyRef moxv1alpha2.SecretKeyReference, manifest []byte) (*moxv1alpha2.EncryptedData, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	encryptedKey, err := seal(key, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	encryptedData, err := seal(dataKey, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &moxv1alpha2.EncryptedData{KeyRef: keyRef, Key: encryptedKey, Data: encryptedData}, nil
+}
+
+// DecryptData descriptografa o objeto criptografado por EncryptData com a chave key.
+func DecryptData(key []byte, encrypted moxv1alpha2.EncryptedData) ([]byte, error) {
+	dataKey, err := open(key, encrypted.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data key: %w", err)
+	}
+	manifest, err := open(dataKey, encrypted.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data: %w", err)
+	}
+	return manifest, nil
+}
+
+// encryptionKeyRef retorna a referência à chave do encryptionKeySecret.
+func encryptionKeyRef(config utils.WatchConfig) moxv1alpha2.SecretKeyReference {
+	return moxv1alpha2.SecretKeyReference{
+		Namespace: utils.ControllerNamespace,
+		Name:      config.EncryptionKeySecret,
+		Key:       utils.EncryptionKeySecretKey,
+	}
+}
+
+func encryptWithKeyRef(ctx context.Context, c client.Client, keyRef moxv1alpha2.SecretKeyReference,
+	manifest []byte) (*moxv1alpha2.EncryptedData, error) {
+	if keyRef.Name == "" {
+		return nil, fmt.Errorf("encryptionKeySecret is not set in the ConfigMap")
+	}
+	key, err := secretKeyValue(ctx, c, keyRef, "")
+	if err != nil {
+		return nil, err
+	}
+	return EncryptData(key, keyRef, manifest)
+}
+
+// secretKeyValue retorna o valor da chave do Secret referenciado. O namespace é usado quando a referência
+// não tem namespace.
+func secretKeyValue(ctx context.Context, c client.Client, ref moxv1alpha2.SecretKeyReference, namespace string) ([]byte, error) {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	value, found := secret.Data[ref.Key]
+	if !found {
+		return nil, fmt.Errorf("secret %s/%s has no key %s", namespace, ref.Name, ref.Key)
+	}
+	return value, nil
+}
+
+// seal criptografa com AES-GCM e retorna o nonce seguido do texto criptografado, em base64.
+func seal(key, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// open descriptografa o valor gerado por seal.
+func open(key []byte, sealed string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted value is too short")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("the encryption key must have 32 bytes, it has %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,217 @@
+package trashedresources
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSecretWithData() *corev1.Secret {
+	secret := newSecret("default", nil)
+	secret.Data = map[string][]byte{"password": []byte("s3cr3t")}
+	return secret
+}
+
+func newProtectionClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = moxv1alpha1.AddToScheme(scheme)
+	_ = moxv1alpha2.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func captureSecret(g *WithT, c client.Client, reconciler *TRReconciler) moxv1alpha2.TrashedResource {
+	g.Expect(CreateOrUpdatedManifest(c, newSecretWithData(), reconciler, "deleted", nil)).To(BeTrue())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	// "s3cr3t" em base64
+	g.Expect(list.Items[0].Spec.Data).NotTo(ContainSubstring("czNjcjN0"))
+	g.Expect(list.Items[0].Spec.Data).To(ContainSubstring("password: " + RedactedValue))
+	return list.Items[0]
+}
+
+func TestRedactManifest(t *testing.T) {
+	g := NewWithT(t)
+
+	redacted, err := RedactManifest([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n" +
+		"data:\n  key: value\nbinaryData:\n  bin: AQI=\n"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(redacted)).To(ContainSubstring("key: " + RedactedValue))
+	g.Expect(string(redacted)).To(ContainSubstring("bin: " + RedactedValue))
+	g.Expect(string(redacted)).To(ContainSubstring("name: cfg"))
+	g.Expect(string(redacted)).NotTo(ContainSubstring("value"))
+
+	_, err = RedactManifest([]byte("- not an object"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRedactManifest_LastAppliedConfiguration(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep:        "60",
+		DataProtectionByKind: map[string]string{"secret": utils.DataProtectionRedact},
+	})
+	applied := newSecretWithData()
+	applied.Annotations = map[string]string{
+		corev1.LastAppliedConfigAnnotation: `{"apiVersion":"v1","kind":"Secret","data":{"password":"czNjcjN0"}}`,
+		"team":                             "payments",
+	}
+
+	g.Expect(CreateOrUpdatedManifest(c, applied, reconciler, "deleted", nil)).To(BeTrue())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	// O kubectl apply guarda uma cópia do Secret na anotação, ela também é removida
+	g.Expect(list.Items[0].Spec.Data).NotTo(ContainSubstring("czNjcjN0"))
+	g.Expect(list.Items[0].Spec.Data).To(ContainSubstring(corev1.LastAppliedConfigAnnotation + ": " + RedactedValue))
+	g.Expect(list.Items[0].Spec.Data).To(ContainSubstring("team: payments"))
+}
+
+func TestEncryptData(t *testing.T) {
+	g := NewWithT(t)
+	key := bytes.Repeat([]byte{1}, 32)
+
+	encrypted, err := EncryptData(key, moxv1alpha2.SecretKeyReference{Name: "key", Key: "key"}, []byte("manifest"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(encrypted.Data).NotTo(ContainSubstring("manifest"))
+
+	manifest, err := DecryptData(key, *encrypted)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).To(Equal("manifest"))
+
+	_, err = DecryptData(bytes.Repeat([]byte{2}, 32), *encrypted)
+	g.Expect(err).To(HaveOccurred())
+	_, err = EncryptData([]byte("short"), moxv1alpha2.SecretKeyReference{}, []byte("manifest"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestCreateOrUpdatedManifest_DataSecret(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+
+	trashed := captureSecret(g, c, newReconciler(utils.WatchConfig{MinutesToKeep: "60"}))
+	g.Expect(trashed.Spec.DataProtection).To(Equal(moxv1alpha2.DataSecret))
+	g.Expect(trashed.Spec.DataSecretRef.Name).To(Equal(trashed.Name))
+
+	secret := &corev1.Secret{}
+	g.Expect(c.Get(context.Background(), types.NamespacedName{Name: trashed.Name, Namespace: "default"}, secret)).To(Succeed())
+	g.Expect(IsDataSecret(secret)).To(BeTrue())
+	g.Expect(secret.OwnerReferences).To(HaveLen(1))
+	g.Expect(secret.OwnerReferences[0].Kind).To(Equal("TrashedResource"))
+
+	manifest, err := RestorableManifest(context.Background(), c, &trashed, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).To(ContainSubstring("password: czNjcjN0"))
+}
+
+func TestCreateOrUpdatedManifest_Encrypted(t *testing.T) {
+	g := NewWithT(t)
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "trashed-resources-key", Namespace: utils.ControllerNamespace},
+		Data:       map[string][]byte{utils.EncryptionKeySecretKey: bytes.Repeat([]byte{1}, 32)},
+	}
+	c := newProtectionClient(keySecret)
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep:        "60",
+		DataProtectionByKind: map[string]string{"secret": utils.DataProtectionEncrypt},
+		EncryptionKeySecret:  "trashed-resources-key",
+	})
+
+	trashed := captureSecret(g, c, reconciler)
+	g.Expect(trashed.Spec.DataProtection).To(Equal(moxv1alpha2.DataEncrypted))
+	g.Expect(trashed.Spec.Encrypted.KeyRef.Namespace).To(Equal(utils.ControllerNamespace))
+
+	manifest, err := RestorableManifest(context.Background(), c, &trashed, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).To(ContainSubstring("password: czNjcjN0"))
+}
+
+func TestCreateOrUpdatedManifest_EncryptedExternal(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	root := t.TempDir()
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "trashed-resources-key", Namespace: utils.ControllerNamespace},
+		Data:       map[string][]byte{utils.EncryptionKeySecretKey: bytes.Repeat([]byte{1}, 32)},
+	}
+	c := newProtectionClient(keySecret)
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep:        "60",
+		MaxInlineSize:        64,
+		StorageBackend:       "file://" + root,
+		DataProtectionByKind: map[string]string{"secret": utils.DataProtectionEncrypt},
+		EncryptionKeySecret:  "trashed-resources-key",
+	})
+
+	g.Expect(CreateOrUpdatedManifest(c, newSecretWithData(), reconciler, "deleted", nil)).To(BeTrue())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(ctx, list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	trashed := list.Items[0]
+
+	// O objeto criptografado também é contado e guardado no storageBackend
+	g.Expect(trashed.Spec.DataProtection).To(Equal(moxv1alpha2.DataEncrypted))
+	g.Expect(trashed.Spec.Data).To(BeEmpty())
+	g.Expect(trashed.Spec.Encrypted.Data).To(BeEmpty())
+	g.Expect(trashed.Spec.Encrypted.Key).NotTo(BeEmpty())
+	g.Expect(trashed.Spec.External.EncryptedKey).To(Equal("default/" + trashed.Name + ".encrypted"))
+	g.Expect(filepath.Join(root, "default", trashed.Name+".encrypted")).To(BeAnExistingFile())
+	g.Expect(trashed.Spec.Size.Stored).To(BeNumerically(">", trashed.Spec.Size.Original))
+
+	manifest, err := RestorableManifest(ctx, c, &trashed, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(manifest)).To(ContainSubstring("password: czNjcjN0"))
+
+	g.Expect(ReleaseExternalData(ctx, c, (*utils.TrashedResourceReconciler)(reconciler).CurrentConfig(), &trashed)).
+		To(Succeed())
+	g.Expect(filepath.Join(root, "default", trashed.Name)).NotTo(BeAnExistingFile())
+	g.Expect(filepath.Join(root, "default", trashed.Name+".encrypted")).NotTo(BeAnExistingFile())
+}
+
+func TestCreateOrUpdatedManifest_EncryptionKeyMissing(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep:        "60",
+		DataProtectionByKind: map[string]string{"secret": utils.DataProtectionEncrypt},
+		EncryptionKeySecret:  "trashed-resources-key",
+	})
+
+	// Sem a chave o objeto fica apenas sem os valores sensíveis
+	trashed := captureSecret(g, c, reconciler)
+	g.Expect(trashed.Spec.DataProtection).To(Equal(moxv1alpha2.DataRedacted))
+	g.Expect(trashed.Spec.Encrypted).To(BeNil())
+
+	_, err := RestorableManifest(context.Background(), c, &trashed, "")
+	g.Expect(err).To(MatchError(ContainSubstring("was redacted")))
+}
+
+func TestCreateOrUpdatedManifest_NoDataProtection(t *testing.T) {
+	g := NewWithT(t)
+	c := newProtectionClient()
+	reconciler := newReconciler(utils.WatchConfig{
+		MinutesToKeep:        "60",
+		DataProtectionByKind: map[string]string{"secret": utils.DataProtectionNone},
+	})
+
+	g.Expect(CreateOrUpdatedManifest(c, newSecretWithData(), reconciler, "deleted", nil)).To(BeTrue())
+	list := &moxv1alpha2.TrashedResourceList{}
+	g.Expect(c.List(context.Background(), list)).To(Succeed())
+	g.Expect(list.Items[0].Spec.DataProtection).To(BeEmpty())
+	g.Expect(list.Items[0].Spec.Data).To(ContainSubstring("password: czNjcjN0"))
+}
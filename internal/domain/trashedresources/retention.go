@@ -0,0 +1,69 @@
+package trashedresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+
+	utils "trashed-resources/internal/utils"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	RetentionRuleNamespaceAnnotation = "namespace-annotation"
+	RetentionRuleNamespace           = "namespace"
+	RetentionRuleKind                = "kind"
+	RetentionRulePolicy              = "policy"
+	RetentionRuleDefault             = "default"
+)
+
+// ResolveRetention escolhe por quanto tempo o objeto é mantido e retorna a regra escolhida, que é gravada
+// no TrashedResource. A regra mais específica vence: anotação do namespace, retentionByNamespace,
+// retentionByKind, retention da policy e, por fim, a retention global do ConfigMap.
+func ResolveRetention(config utils.WatchConfig, kubernetesObject client.Object,
+	namespaceAnnotations map[string]string, policy *moxv1alpha1.TrashedResourcePolicy) (time.Duration, string) {
+	namespace := kubernetesObject.GetNamespace()
+
+	if value, found := namespaceAnnotations[utils.RetentionAnnotation]; found {
+		duration, err := utils.ParseRetention(value)
+		if err == nil {
+			return duration, RetentionRuleNamespaceAnnotation
+		}
+		logger.Error(err, "Invalid retention annotation on namespace, ignoring", "namespace", namespace)
+	}
+
+	if duration, found := config.RetentionByNamespace[namespace]; found && namespace != "" {
+		return duration, fmt.Sprintf("%s:%s", RetentionRuleNamespace, namespace)
+	}
+
+	kind := kubernetesObject.GetObjectKind().GroupVersionKind().Kind
+	if duration, found := config.RetentionByKind[strings.ToLower(kind)]; found {
+		return duration, fmt.Sprintf("%s:%s", RetentionRuleKind, kind)
+	}
+
+	if policy != nil && policy.Spec.Retention != nil {
+		return utils.GetDurationToKeep(*policy.Spec.Retention), fmt.Sprintf("%s:%s", RetentionRulePolicy, policy.Name)
+	}
+
+	return utils.GetDurationToKeepFromConfig(config), RetentionRuleDefault
+}
+
+// getNamespaceAnnotations retorna as anotações do namespace do objeto, ou nil para objetos de cluster.
+func getNamespaceAnnotations(ctx context.Context, c client.Client, namespace string) map[string]string {
+	if namespace == "" {
+		return nil
+	}
+	namespaceObject := &v1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, namespaceObject); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to get namespace annotations", "namespace", namespace)
+		}
+		return nil
+	}
+	return namespaceObject.Annotations
+}
@@ -0,0 +1,61 @@
+package trashedresources
+
+import (
+	"testing"
+	"time"
+
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+	utils "trashed-resources/internal/utils"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveRetention(t *testing.T) {
+	g := NewWithT(t)
+
+	config := utils.WatchConfig{
+		MinutesToKeep:        "10",
+		RetentionByKind:      map[string]time.Duration{"secret": 30 * 24 * time.Hour},
+		RetentionByNamespace: map[string]time.Duration{"audit": 90 * 24 * time.Hour},
+	}
+	policy := newPolicy("secrets", []moxv1alpha1.PolicyKind{{Kind: "Secret"}}, moxv1alpha1.ActionDelete)
+	policy.Spec.Retention = &moxv1alpha1.Retention{Hours: 2}
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: "default"},
+	}
+
+	// Global retention
+	duration, rule := ResolveRetention(config, pod, nil, nil)
+	g.Expect(duration).To(Equal(10 * time.Minute))
+	g.Expect(rule).To(Equal(RetentionRuleDefault))
+
+	// Policy retention
+	duration, rule = ResolveRetention(config, pod, nil, &policy)
+	g.Expect(duration).To(Equal(2 * time.Hour))
+	g.Expect(rule).To(Equal("policy:secrets"))
+
+	// Kind overrides the policy
+	duration, rule = ResolveRetention(config, newSecret("default", nil), nil, &policy)
+	g.Expect(duration).To(Equal(30 * 24 * time.Hour))
+	g.Expect(rule).To(Equal("kind:Secret"))
+
+	// Namespace overrides the kind
+	duration, rule = ResolveRetention(config, newSecret("audit", nil), nil, &policy)
+	g.Expect(duration).To(Equal(90 * 24 * time.Hour))
+	g.Expect(rule).To(Equal("namespace:audit"))
+
+	// Namespace annotation overrides everything
+	annotations := map[string]string{utils.RetentionAnnotation: "7d"}
+	duration, rule = ResolveRetention(config, newSecret("audit", nil), annotations, &policy)
+	g.Expect(duration).To(Equal(7 * 24 * time.Hour))
+	g.Expect(rule).To(Equal(RetentionRuleNamespaceAnnotation))
+
+	// Invalid annotation is ignored
+	annotations = map[string]string{utils.RetentionAnnotation: "forever"}
+	duration, rule = ResolveRetention(config, newSecret("audit", nil), annotations, &policy)
+	g.Expect(duration).To(Equal(90 * 24 * time.Hour))
+	g.Expect(rule).To(Equal("namespace:audit"))
+}
@@ -0,0 +1,165 @@
+package trashedresources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ActionFromType converte o actionType usado no nome do TrashedResource (deleted, updated) na ação capturada.
+func ActionFromType(actionType string) moxv1alpha2.TrashedAction {
+	if actionType == "updated" {
+		return moxv1alpha2.ActionUpdate
+	}
+	return moxv1alpha2.ActionDelete
+}
+
+// BuildStatus calcula o status do TrashedResource a partir do seu spec, labels e anotações.
+// As fases Restored e RestoreFailed são definidas pelo restore e mantidas. Retorna também
+// em quanto tempo o TrashedResource passa a Expiring (zero quando já está ou nunca passará).
+func BuildStatus(trashedResource *moxv1alpha2.TrashedResource, now time.Time) (moxv1alpha2.TrashedResourceStatus, time.Duration) {
+	status := *trashedResource.Status.DeepCopy()
+	generation := trashedResource.Generation
+
+	original, err := OriginalOf(trashedResource)
+	captured := metav1.Condition{
+		Type:               moxv1alpha2.ConditionCaptured,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Captured",
+		Message:            "The object is stored in spec.data",
+		ObservedGeneration: generation,
+	}
+	if external := trashedResource.Spec.External; external != nil {
+		captured.Message = fmt.Sprintf("The object is stored in %s as %s", external.URL, external.Key)
+	}
+	if err != nil {
+		captured.Status = metav1.ConditionFalse
+		captured.Reason = "InvalidData"
+		captured.Message = err.Error()
+	}
+	status.Original = original
+	status.Action = ActionOf(trashedResource)
+	meta.SetStatusCondition(&status.Conditions, captured)
+
+	if trashedResource.Spec.KeepUntil.IsZero() {
+		status.ExpiresAt = nil
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               moxv1alpha2.ConditionExpiring,
+			Status:             metav1.ConditionUnknown,
+			Reason:             "InvalidKeepUntil",
+			Message:            "spec.keepUntil is not a valid timestamp, the TrashedResource will not expire",
+			ObservedGeneration: generation,
+		})
+		if status.Phase == "" || status.Phase == moxv1alpha2.PhaseExpiring {
+			status.Phase = moxv1alpha2.PhaseActive
+		}
+		return status, 0
+	}
+	keepUntil := trashedResource.Spec.KeepUntil.Time
+	status.ExpiresAt = trashedResource.Spec.KeepUntil.DeepCopy()
+
+	retention := time.Duration(0)
+	if !trashedResource.CreationTimestamp.IsZero() {
+		retention = keepUntil.Sub(trashedResource.CreationTimestamp.Time)
+	}
+	expiringAt := keepUntil.Add(-time.Duration(float64(retention) * moxv1alpha2.ExpiringThreshold))
+	untilExpiring := expiringAt.Sub(now)
+
+	expiring := metav1.Condition{
+		Type:               moxv1alpha2.ConditionExpiring,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RetentionActive",
+		Message:            "The TrashedResource is kept until " + status.ExpiresAt.UTC().Format(time.RFC3339),
+		ObservedGeneration: generation,
+	}
+	if untilExpiring <= 0 {
+		expiring.Status = metav1.ConditionTrue
+		expiring.Reason = "RetentionEnding"
+		untilExpiring = 0
+	}
+	meta.SetStatusCondition(&status.Conditions, expiring)
+
+	if status.Phase != moxv1alpha2.PhaseRestored && status.Phase != moxv1alpha2.PhaseRestoreFailed {
+		status.Phase = moxv1alpha2.PhaseActive
+		if expiring.Status == metav1.ConditionTrue {
+			status.Phase = moxv1alpha2.PhaseExpiring
+		}
+	}
+
+	return status, untilExpiring
+}
+
+// OriginalOf retorna a identidade do objeto capturado: spec.original quando preenchido, senão a do objeto
+// decodificado de spec.data, com o nome da anotação OriginalName quando ele não tem nome. O erro de
+// decodificação de spec.data é retornado junto com a identidade que foi possível obter. Objetos guardados
+// no storageBackend não são lidos.
+func OriginalOf(trashedResource *moxv1alpha2.TrashedResource) (moxv1alpha2.OriginalObject, error) {
+	original := moxv1alpha2.OriginalObject{}
+	var err error
+	if trashedResource.Spec.External == nil {
+		var data []byte
+		if data, err = InlineData(trashedResource); err == nil {
+			original, err = decodeOriginalObject(string(data))
+		}
+	}
+	if reference := trashedResource.Spec.Original; reference.Kind != "" {
+		original = originalFromReference(reference)
+	}
+	if original.Name == "" {
+		original.Name = trashedResource.Annotations["OriginalName"]
+	}
+	return original, err
+}
+
+func decodeOriginalObject(data string) (moxv1alpha2.OriginalObject, error) {
+	object := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(data), 4096).Decode(object); err != nil {
+		return moxv1alpha2.OriginalObject{}, err
+	}
+	gvk := object.GroupVersionKind()
+	return moxv1alpha2.OriginalObject{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Name:      object.GetName(),
+		Namespace: object.GetNamespace(),
+		UID:       object.GetUID(),
+	}, nil
+}
+
+func originalFromReference(reference moxv1alpha2.OriginalReference) moxv1alpha2.OriginalObject {
+	gv, _ := schema.ParseGroupVersion(reference.APIVersion)
+	return moxv1alpha2.OriginalObject{
+		Group:     gv.Group,
+		Version:   gv.Version,
+		Kind:      reference.Kind,
+		Name:      reference.Name,
+		Namespace: reference.Namespace,
+		UID:       reference.UID,
+	}
+}
+
+// ActionOf retorna a ação capturada pelo TrashedResource. Usa spec.action e, para TrashedResources
+// anteriores a ele, o label da ação ou o prefixo do nome.
+func ActionOf(trashedResource *moxv1alpha2.TrashedResource) moxv1alpha2.TrashedAction {
+	if trashedResource.Spec.Action != "" {
+		return trashedResource.Spec.Action
+	}
+	if action := trashedResource.Labels[moxv1alpha2.ActionLabel]; action != "" {
+		return moxv1alpha2.TrashedAction(action)
+	}
+	if strings.HasPrefix(trashedResource.Name, "trashed-updated-") {
+		return moxv1alpha2.ActionUpdate
+	}
+	if strings.HasPrefix(trashedResource.Name, "trashed-deleted-") {
+		return moxv1alpha2.ActionDelete
+	}
+	return ""
+}
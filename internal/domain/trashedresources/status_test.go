@@ -0,0 +1,143 @@
+package trashedresources
+
+import (
+	"testing"
+	"time"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const secretData = `apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+  namespace: default
+  uid: 1234-abcd
+`
+
+func newTrashedResource(name, data string, created, keepUntil time.Time) *moxv1alpha2.TrashedResource {
+	return &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			Data:      data,
+			KeepUntil: metav1.NewTime(keepUntil),
+		},
+	}
+}
+
+func TestBuildStatus(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now().Truncate(time.Second)
+
+	tr := newTrashedResource("trashed-deleted-secret-my-secret-20260101-000000", secretData,
+		now.Add(-time.Hour), now.Add(9*time.Hour))
+	status, untilExpiring := BuildStatus(tr, now)
+
+	g.Expect(status.Phase).To(Equal(moxv1alpha2.PhaseActive))
+	g.Expect(status.Action).To(Equal(moxv1alpha2.ActionDelete))
+	g.Expect(status.Original).To(Equal(moxv1alpha2.OriginalObject{
+		Version:   "v1",
+		Kind:      "Secret",
+		Name:      "my-secret",
+		Namespace: "default",
+		UID:       "1234-abcd",
+	}))
+	g.Expect(status.ExpiresAt.Time.Equal(now.Add(9 * time.Hour))).To(BeTrue())
+	g.Expect(untilExpiring).To(Equal(8 * time.Hour))
+	g.Expect(meta.IsStatusConditionTrue(status.Conditions, moxv1alpha2.ConditionCaptured)).To(BeTrue())
+	g.Expect(meta.IsStatusConditionFalse(status.Conditions, moxv1alpha2.ConditionExpiring)).To(BeTrue())
+
+	// Last 10% of the retention
+	tr = newTrashedResource("trashed-updated-secret-my-secret-20260101-000000", secretData,
+		now.Add(-9*time.Hour-30*time.Minute), now.Add(30*time.Minute))
+	status, untilExpiring = BuildStatus(tr, now)
+	g.Expect(status.Phase).To(Equal(moxv1alpha2.PhaseExpiring))
+	g.Expect(status.Action).To(Equal(moxv1alpha2.ActionUpdate))
+	g.Expect(untilExpiring).To(BeZero())
+	g.Expect(meta.IsStatusConditionTrue(status.Conditions, moxv1alpha2.ConditionExpiring)).To(BeTrue())
+}
+
+func TestBuildStatus_KeepsRestorePhaseAndActionLabel(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+
+	tr := newTrashedResource("custom-name", secretData, now, now.Add(time.Hour))
+	tr.Labels = map[string]string{moxv1alpha2.ActionLabel: string(moxv1alpha2.ActionUpdate)}
+	tr.Status.Phase = moxv1alpha2.PhaseRestoreFailed
+
+	status, _ := BuildStatus(tr, now)
+	g.Expect(status.Phase).To(Equal(moxv1alpha2.PhaseRestoreFailed))
+	g.Expect(status.Action).To(Equal(moxv1alpha2.ActionUpdate))
+}
+
+func TestBuildStatus_InvalidData(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+
+	tr := newTrashedResource("trashed-deleted-secret-my-secret", "{ not yaml", now, now.Add(time.Hour))
+	tr.Annotations = map[string]string{"OriginalName": "my-secret"}
+
+	status, _ := BuildStatus(tr, now)
+	g.Expect(status.Original.Name).To(Equal("my-secret"))
+	captured := meta.FindStatusCondition(status.Conditions, moxv1alpha2.ConditionCaptured)
+	g.Expect(captured).NotTo(BeNil())
+	g.Expect(captured.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(captured.Reason).To(Equal("InvalidData"))
+}
+
+func TestActionFromType(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(ActionFromType("updated")).To(Equal(moxv1alpha2.ActionUpdate))
+	g.Expect(ActionFromType("deleted")).To(Equal(moxv1alpha2.ActionDelete))
+}
+
+func TestBuildStatus_UsesSpecOriginal(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+
+	tr := newTrashedResource("custom-name", secretData, now, now.Add(time.Hour))
+	tr.Spec.Original = moxv1alpha2.OriginalReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       "nginx",
+		Namespace:  "web",
+		UID:        "5678",
+	}
+	tr.Spec.Action = moxv1alpha2.ActionUpdate
+
+	status, _ := BuildStatus(tr, now)
+	g.Expect(status.Original).To(Equal(moxv1alpha2.OriginalObject{
+		Group:     "apps",
+		Version:   "v1",
+		Kind:      "Deployment",
+		Name:      "nginx",
+		Namespace: "web",
+		UID:       "5678",
+	}))
+	g.Expect(status.Action).To(Equal(moxv1alpha2.ActionUpdate))
+}
+
+func TestBuildStatus_WithoutKeepUntil(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+
+	tr := newTrashedResource("trashed-deleted-secret-my-secret", secretData, now, now)
+	tr.Spec.KeepUntil = metav1.Time{}
+
+	status, untilExpiring := BuildStatus(tr, now)
+	g.Expect(status.Phase).To(Equal(moxv1alpha2.PhaseActive))
+	g.Expect(status.ExpiresAt).To(BeNil())
+	g.Expect(untilExpiring).To(BeZero())
+	expiring := meta.FindStatusCondition(status.Conditions, moxv1alpha2.ConditionExpiring)
+	g.Expect(expiring).NotTo(BeNil())
+	g.Expect(expiring.Status).To(Equal(metav1.ConditionUnknown))
+	g.Expect(expiring.Reason).To(Equal("InvalidKeepUntil"))
+}
@@ -0,0 +1,43 @@
+package trashedresources
+
+import (
+	"context"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// CapturedByFinalizer é o valor da anotação mox.app.br/captured dos objetos capturados antes de o finalizer
+// mox.app.br/trash-protection ser removido.
+const CapturedByFinalizer = "finalizer"
+
+// AddTrashProtection adiciona o finalizer mox.app.br/trash-protection ao objeto. O patch falha quando o
+// objeto mudou desde que foi lido, para não apagar os finalizers adicionados por outros controllers.
+func AddTrashProtection(ctx context.Context, c client.Client, object client.Object) error {
+	if controllerutil.ContainsFinalizer(object, moxv1alpha2.TrashProtectionFinalizer) {
+		return nil
+	}
+	original := object.DeepCopyObject().(client.Object)
+	controllerutil.AddFinalizer(object, moxv1alpha2.TrashProtectionFinalizer)
+	return c.Patch(ctx, object, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// ReleaseTrashProtection remove o finalizer mox.app.br/trash-protection do objeto. Quando captured, o objeto
+// recebe também a anotação mox.app.br/captured, assim a sua deleção não é capturada outra vez.
+func ReleaseTrashProtection(ctx context.Context, c client.Client, object client.Object, captured bool) error {
+	if !controllerutil.ContainsFinalizer(object, moxv1alpha2.TrashProtectionFinalizer) {
+		return nil
+	}
+	original := object.DeepCopyObject().(client.Object)
+	controllerutil.RemoveFinalizer(object, moxv1alpha2.TrashProtectionFinalizer)
+	if captured {
+		annotations := object.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[moxv1alpha2.CapturedAnnotation] = CapturedByFinalizer
+		object.SetAnnotations(annotations)
+	}
+	return c.Patch(ctx, object, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
@@ -0,0 +1,40 @@
+package trashedresources
+
+import (
+	"context"
+	"testing"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTrashProtection(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default",
+		Finalizers: []string{"example.com/other"}}}
+	c := newProtectionClient(configMap)
+
+	g.Expect(AddTrashProtection(ctx, c, configMap)).To(Succeed())
+	g.Expect(AddTrashProtection(ctx, c, configMap)).To(Succeed())
+	stored := &corev1.ConfigMap{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), stored)).To(Succeed())
+	g.Expect(stored.Finalizers).To(Equal([]string{"example.com/other", moxv1alpha2.TrashProtectionFinalizer}))
+
+	// O patch não sobrescreve um objeto alterado desde que foi lido
+	outdated := stored.DeepCopy()
+	stored.Data = map[string]string{"key": "value"}
+	g.Expect(c.Update(ctx, stored)).To(Succeed())
+	err := ReleaseTrashProtection(ctx, c, outdated, true)
+	g.Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+	g.Expect(ReleaseTrashProtection(ctx, c, stored, true)).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), stored)).To(Succeed())
+	g.Expect(stored.Finalizers).To(Equal([]string{"example.com/other"}))
+	g.Expect(stored.Annotations).To(HaveKeyWithValue(moxv1alpha2.CapturedAnnotation, CapturedByFinalizer))
+	g.Expect(ReleaseTrashProtection(ctx, c, stored, true)).To(Succeed())
+}
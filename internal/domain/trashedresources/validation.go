@@ -0,0 +1,89 @@
+package trashedresources
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	utils "trashed-resources/internal/utils"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ValidateData verifica se o spec.data pode ser decodificado como no restore: um objeto com apiVersion,
+// kind e nome, ou a anotação OriginalName. O objeto guardado no storageBackend não é lido, apenas a
+// referência a ele é verificada.
+func ValidateData(trashed *moxv1alpha2.TrashedResource) error {
+	if external := trashed.Spec.External; external != nil {
+		if external.URL == "" || external.Key == "" {
+			return fmt.Errorf("spec.external must have the url and the key of the stored object")
+		}
+		if trashed.Spec.Data != "" {
+			return fmt.Errorf("spec.data must be empty when the object is stored in spec.external")
+		}
+		return nil
+	}
+
+	data, err := InlineData(trashed)
+	if err != nil {
+		return fmt.Errorf("spec.data can not be decoded: %w", err)
+	}
+	object := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(data)), 4096).Decode(object); err != nil {
+		return fmt.Errorf("spec.data is not a Kubernetes object: %w", err)
+	}
+	if object.GetAPIVersion() == "" || object.GetKind() == "" {
+		return fmt.Errorf("spec.data must have the apiVersion and the kind of the object")
+	}
+	if object.GetName() == "" && trashed.Annotations["OriginalName"] == "" {
+		return fmt.Errorf("spec.data must have the name of the object, or the OriginalName annotation")
+	}
+	return nil
+}
+
+// ValidateReferences verifica as referências que o controller segue com as suas permissões: o spec.external
+// tem que ser o storageBackend da ConfigMap com as chaves de ExternalKeys e os Secrets referenciados têm que
+// estar no namespace do TrashedResource, exceto o encryptionKeySecret da ConfigMap.
+func ValidateReferences(trashed *moxv1alpha2.TrashedResource, config utils.WatchConfig) field.ErrorList {
+	spec := field.NewPath("spec")
+	errs := field.ErrorList{}
+	if external := trashed.Spec.External; external != nil {
+		path := spec.Child("external")
+		key, encryptedKey := ExternalKeys(trashed)
+		if external.URL != config.StorageBackend {
+			errs = append(errs, field.Invalid(path.Child("url"), external.URL, "must be the storageBackend of the ConfigMap"))
+		}
+		if external.Key != key {
+			errs = append(errs, field.Invalid(path.Child("key"), external.Key, fmt.Sprintf("must be %s", key)))
+		}
+		if external.EncryptedKey != "" && external.EncryptedKey != encryptedKey {
+			errs = append(errs, field.Invalid(path.Child("encryptedKey"), external.EncryptedKey,
+				fmt.Sprintf("must be %s", encryptedKey)))
+		}
+	}
+	if ref := trashed.Spec.DataSecretRef; ref != nil && ref.Namespace != "" && ref.Namespace != trashed.Namespace {
+		errs = append(errs, field.Forbidden(spec.Child("dataSecretRef", "namespace"),
+			"the Secret must be in the namespace of the TrashedResource"))
+	}
+	if encrypted := trashed.Spec.Encrypted; encrypted != nil {
+		ref := encrypted.KeyRef
+		if ref.Namespace != "" && ref.Namespace != trashed.Namespace && ref != encryptionKeyRef(config) {
+			errs = append(errs, field.Forbidden(spec.Child("encrypted", "keyRef", "namespace"),
+				"the Secret must be in the namespace of the TrashedResource, or be the encryptionKeySecret"))
+		}
+	}
+	return errs
+}
+
+// ValidateFinalizers verifica que o finalizer mox.app.br/external-data, que faz o controller apagar o objeto
+// do storageBackend, só está nos TrashedResources com o spec.external.
+func ValidateFinalizers(trashed *moxv1alpha2.TrashedResource) field.ErrorList {
+	if trashed.Spec.External == nil && slices.Contains(trashed.Finalizers, moxv1alpha2.ExternalDataFinalizer) {
+		return field.ErrorList{field.Forbidden(field.NewPath("metadata", "finalizers"),
+			fmt.Sprintf("%s requires spec.external", moxv1alpha2.ExternalDataFinalizer))}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package trashedresources
+
+import (
+	"testing"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateData(t *testing.T) {
+	g := NewWithT(t)
+	trashed := func(data string) *moxv1alpha2.TrashedResource {
+		return &moxv1alpha2.TrashedResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "trashed", Namespace: "default"},
+			Spec:       moxv1alpha2.TrashedResourceSpec{Data: data},
+		}
+	}
+
+	g.Expect(ValidateData(trashed("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"))).To(Succeed())
+	g.Expect(ValidateData(trashed(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`))).To(Succeed())
+
+	g.Expect(ValidateData(trashed(""))).NotTo(Succeed())
+	g.Expect(ValidateData(trashed("- not\n- an object\n"))).NotTo(Succeed())
+	g.Expect(ValidateData(trashed("kind: ConfigMap\nmetadata:\n  name: cm\n"))).NotTo(Succeed())
+	g.Expect(ValidateData(trashed("apiVersion: v1\nkind: ConfigMap\n"))).NotTo(Succeed())
+
+	// O nome pode vir da anotação OriginalName
+	unnamed := trashed("apiVersion: v1\nkind: ConfigMap\n")
+	unnamed.Annotations = map[string]string{"OriginalName": "cm"}
+	g.Expect(ValidateData(unnamed)).To(Succeed())
+
+	encoded, err := EncodeData([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"),
+		moxv1alpha2.DataEncodingGzip)
+	g.Expect(err).NotTo(HaveOccurred())
+	compressed := trashed(encoded)
+	compressed.Spec.Encoding = moxv1alpha2.DataEncodingGzip
+	g.Expect(ValidateData(compressed)).To(Succeed())
+	compressed.Spec.Data = "not gzip"
+	g.Expect(ValidateData(compressed)).NotTo(Succeed())
+
+	external := trashed("")
+	external.Spec.External = &moxv1alpha2.ExternalData{URL: "file:///trash", Key: "default/trashed"}
+	g.Expect(ValidateData(external)).To(Succeed())
+	external.Spec.Data = "apiVersion: v1"
+	g.Expect(ValidateData(external)).NotTo(Succeed())
+	external.Spec.Data = ""
+	external.Spec.External.Key = ""
+	g.Expect(ValidateData(external)).NotTo(Succeed())
+}
@@ -0,0 +1,123 @@
+// Package metrics registers the trashedresources metrics in the controller-runtime registry, served by the
+// manager on its metrics endpoint.
+package metrics
+
+import (
+	"context"
+	"errors"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Reasons of a failed capture.
+const (
+	CaptureErrorEncode  = "encode"
+	CaptureErrorProtect = "protect"
+	CaptureErrorStore   = "store"
+	CaptureErrorCreate  = "create"
+)
+
+var (
+	// Captured counts the TrashedResources created, by kind of the original object, action and namespace.
+	Captured = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "trashedresources_captured_total",
+		Help: "Number of objects captured in a TrashedResource.",
+	}, []string{"kind", "action", "namespace"})
+
+	// CaptureErrors counts the objects that should have been captured but were not, by reason.
+	CaptureErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "trashedresources_capture_errors_total",
+		Help: "Number of objects that failed to be captured in a TrashedResource.",
+	}, []string{"kind", "action", "reason"})
+
+	// Expired counts the TrashedResources deleted by the controller at the end of their retention.
+	Expired = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "trashedresources_expired_total",
+		Help: "Number of TrashedResources deleted after their retention.",
+	}, []string{"namespace"})
+
+	// ArchiveErrors counts the expired TrashedResources that could not be archived, they are retried.
+	ArchiveErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "trashedresources_archive_errors_total",
+		Help: "Number of failures to archive an expired TrashedResource.",
+	}, []string{"namespace"})
+
+	// ObjectSize observes the size of the captured objects, and StoredSize their size once encoded.
+	ObjectSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "trashedresources_object_size_bytes",
+		Help:    "Size of the captured objects.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 8),
+	}, []string{"kind"})
+	StoredSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "trashedresources_stored_size_bytes",
+		Help:    "Size of the captured objects once encoded, inline in the TrashedResource or in the storage backend.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 8),
+	}, []string{"kind", "encoding", "location"})
+
+	activeDesc = prometheus.NewDesc("trashedresources_active",
+		"Number of TrashedResources in the cluster, by namespace and phase.", []string{"namespace", "phase"}, nil)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(Captured, CaptureErrors, Expired, ArchiveErrors, ObjectSize, StoredSize)
+}
+
+// ObserveCapture records a TrashedResource created from an object of the kind.
+func ObserveCapture(kind string, trashed *moxv1alpha2.TrashedResource) {
+	Captured.WithLabelValues(kind, string(trashed.Spec.Action), trashed.Namespace).Inc()
+	size := trashed.Spec.Size
+	if size == nil {
+		return
+	}
+	location := "inline"
+	if trashed.Spec.External != nil {
+		location = "external"
+	}
+	encoding := string(trashed.Spec.Encoding)
+	if encoding == "" {
+		encoding = "none"
+	}
+	ObjectSize.WithLabelValues(kind).Observe(float64(size.Original))
+	StoredSize.WithLabelValues(kind, encoding, location).Observe(float64(size.Stored))
+}
+
+// RegisterActive registers the trashedresources_active gauge, counted from the TrashedResources read with
+// reader, usually the cache of the manager, on every scrape. Registering it again does nothing.
+func RegisterActive(reader client.Reader) error {
+	err := ctrlmetrics.Registry.Register(&activeCollector{reader: reader})
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
+
+type activeCollector struct {
+	reader client.Reader
+}
+
+func (c *activeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeDesc
+}
+
+func (c *activeCollector) Collect(ch chan<- prometheus.Metric) {
+	list := &moxv1alpha2.TrashedResourceList{}
+	if err := c.reader.List(context.Background(), list); err != nil {
+		ch <- prometheus.NewInvalidMetric(activeDesc, err)
+		return
+	}
+	type key struct{ namespace, phase string }
+	counts := map[key]int{}
+	for _, trashed := range list.Items {
+		phase := string(trashed.Status.Phase)
+		if phase == "" {
+			phase = "Unknown"
+		}
+		counts[key{trashed.Namespace, phase}]++
+	}
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(activeDesc, prometheus.GaugeValue, float64(count), k.namespace, k.phase)
+	}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestObserveCapture(t *testing.T) {
+	g := NewWithT(t)
+	Captured.Reset()
+	ObjectSize.Reset()
+	StoredSize.Reset()
+
+	ObserveCapture("ConfigMap", &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			Action:   moxv1alpha2.ActionDelete,
+			Encoding: moxv1alpha2.DataEncodingGzip,
+			Size:     &moxv1alpha2.DataSize{Original: 4096, Stored: 512},
+		},
+	})
+	ObserveCapture("ConfigMap", &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+		Spec:       moxv1alpha2.TrashedResourceSpec{Action: moxv1alpha2.ActionDelete},
+	})
+
+	g.Expect(testutil.ToFloat64(Captured.WithLabelValues("ConfigMap", "delete", "team-a"))).To(Equal(2.0))
+	g.Expect(testutil.CollectAndCount(ObjectSize)).To(Equal(1))
+	g.Expect(testutil.CollectAndCompare(StoredSize, strings.NewReader(`
+# HELP trashedresources_stored_size_bytes Size of the captured objects once encoded, inline in the TrashedResource or in the storage backend.
+# TYPE trashedresources_stored_size_bytes histogram
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="1024"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="4096"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="16384"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="65536"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="262144"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="1.048576e+06"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="4.194304e+06"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="1.6777216e+07"} 1
+trashedresources_stored_size_bytes_bucket{encoding="gzip",kind="ConfigMap",location="inline",le="+Inf"} 1
+trashedresources_stored_size_bytes_sum{encoding="gzip",kind="ConfigMap",location="inline"} 512
+trashedresources_stored_size_bytes_count{encoding="gzip",kind="ConfigMap",location="inline"} 1
+`))).To(Succeed())
+}
+
+func TestRegisterActive(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(moxv1alpha2.AddToScheme(scheme)).To(Succeed())
+	newTrashed := func(name, namespace string, phase moxv1alpha2.TrashedResourcePhase) *moxv1alpha2.TrashedResource {
+		return &moxv1alpha2.TrashedResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     moxv1alpha2.TrashedResourceStatus{Phase: phase},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newTrashed("a", "team-a", moxv1alpha2.PhaseActive),
+		newTrashed("b", "team-a", moxv1alpha2.PhaseActive),
+		newTrashed("c", "team-a", moxv1alpha2.PhaseRestored),
+		newTrashed("d", "team-b", ""),
+	).Build()
+
+	g.Expect(RegisterActive(c)).To(Succeed())
+	// Registering it again does not fail
+	g.Expect(RegisterActive(c)).To(Succeed())
+
+	g.Expect(testutil.GatherAndCompare(ctrlmetrics.Registry, strings.NewReader(`
+# HELP trashedresources_active Number of TrashedResources in the cluster, by namespace and phase.
+# TYPE trashedresources_active gauge
+trashedresources_active{namespace="team-a",phase="Active"} 2
+trashedresources_active{namespace="team-a",phase="Restored"} 1
+trashedresources_active{namespace="team-b",phase="Unknown"} 1
+`), "trashedresources_active")).To(Succeed())
+}
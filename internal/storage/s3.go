@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Options configures an S3 compatible backend.
+type S3Options struct {
+	// Endpoint is the URL of the store, https://s3.<region>.amazonaws.com when empty.
+	Endpoint string
+	// Region is us-east-1 when empty.
+	Region string
+	Bucket string
+	// Prefix is prepended to every key.
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// HTTPClient is http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// S3 stores each key as an object of a bucket, using path style requests signed with AWS Signature Version 4,
+// so it works with AWS S3 and with compatible stores like MinIO.
+type S3 struct {
+	options  S3Options
+	endpoint *url.URL
+}
+
+// NewS3 returns an S3 compatible backend.
+func NewS3(options S3Options) (*S3, error) {
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+	if options.Endpoint == "" {
+		options.Endpoint = "https://s3." + options.Region + ".amazonaws.com"
+	}
+	if options.Prefix != "" && !strings.HasSuffix(options.Prefix, "/") {
+		options.Prefix += "/"
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", options.Endpoint)
+	}
+	return &S3{options: options, endpoint: endpoint}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, data []byte) error {
+	response, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (s *S3) Get(ctx context.Context, key string) ([]byte, error) {
+	response, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	return io.ReadAll(response.Body)
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	response, err := s.do(ctx, http.MethodDelete, key, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// do sends the signed request and returns the response when its status is 2xx.
+func (s *S3) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	path := strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + "/" + uriEncode(s.options.Bucket, false) + "/" +
+		uriEncode(s.options.Prefix+key, true)
+	request, err := http.NewRequestWithContext(ctx, method, s.endpoint.Scheme+"://"+s.endpoint.Host+path,
+		bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(request, path, body, time.Now().UTC())
+
+	response, err := s.options.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s %s", method, key, response.Status, strings.TrimSpace(string(message)))
+	}
+	return response, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to the request.
+func (s *S3) sign(request *http.Request, path string, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	request.Header.Set("x-amz-content-sha256", payloadHash)
+	request.Header.Set("x-amz-date", amzDate)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		path,
+		"",
+		"host:" + request.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.options.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.options.SecretAccessKey), date)
+	for _, part := range []string{s.options.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	request.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.options.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// uriEncode encodes every byte except the unreserved characters and, when keepSlash, the slashes.
+func uriEncode(value string, keepSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~',
+			keepSlash && b == '/':
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
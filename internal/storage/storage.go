@@ -0,0 +1,55 @@
+// Package storage keeps the captured objects too large to be stored in their TrashedResource.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when the key does not exist.
+var ErrNotFound = errors.New("object not found in the storage backend")
+
+// Backend stores payloads by key, like default/trashed-updated-configmap-big-20260301-230159.
+type Backend interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the key, it does not fail when the key does not exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// Open returns the backend of the URL:
+//
+//   - file:///var/lib/trashed-resources - a directory, eg. a mounted PersistentVolumeClaim
+//   - s3://bucket/prefix?endpoint=http://minio:9000&region=us-east-1 - an S3 compatible store, with the
+//     credentials of the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+func Open(rawURL string) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage backend %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" || !strings.HasPrefix(u.Path, "/") {
+			return nil, fmt.Errorf("invalid storage backend %q, use an absolute path like file:///data", rawURL)
+		}
+		return NewFilesystem(u.Path), nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid storage backend %q, the bucket is required like s3://bucket/prefix", rawURL)
+		}
+		return NewS3(S3Options{
+			Endpoint:        u.Query().Get("endpoint"),
+			Region:          u.Query().Get("region"),
+			Bucket:          u.Host,
+			Prefix:          strings.TrimPrefix(u.Path, "/"),
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		})
+	}
+	return nil, fmt.Errorf("invalid storage backend %q, the scheme must be file or s3", rawURL)
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// testBackend verifies the behaviour every backend must have.
+func testBackend(g *WithT, backend Backend) {
+	ctx := context.Background()
+
+	g.Expect(backend.Put(ctx, "default/trashed-big", []byte("payload"))).To(Succeed())
+	data, err := backend.Get(ctx, "default/trashed-big")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal("payload"))
+
+	g.Expect(backend.Put(ctx, "default/trashed-big", []byte("replaced"))).To(Succeed())
+	data, err = backend.Get(ctx, "default/trashed-big")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal("replaced"))
+
+	g.Expect(backend.Delete(ctx, "default/trashed-big")).To(Succeed())
+	_, err = backend.Get(ctx, "default/trashed-big")
+	g.Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+	g.Expect(backend.Delete(ctx, "default/trashed-big")).To(Succeed())
+}
+
+func TestFilesystem(t *testing.T) {
+	g := NewWithT(t)
+	testBackend(g, NewFilesystem(t.TempDir()))
+
+	_, err := NewFilesystem(t.TempDir()).Get(context.Background(), "../outside")
+	g.Expect(err).To(MatchError(ContainSubstring("invalid storage key")))
+}
+
+// newS3StandIn returns a server that keeps the objects in memory, like a bucket of an S3 compatible store.
+func newS3StandIn(g *WithT) *httptest.Server {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		g.Expect(r.Header.Get("x-amz-content-sha256")).To(Equal(hex.EncodeToString(sum[:])))
+		g.Expect(r.Header.Get("Authorization")).To(MatchRegexp(
+			`^AWS4-HMAC-SHA256 Credential=access/\d{8}/eu-west-1/s3/aws4_request, ` +
+				`SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`))
+
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			object, found := objects[r.URL.Path]
+			if !found {
+				http.Error(w, "NoSuchKey", http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(object)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestS3(t *testing.T) {
+	g := NewWithT(t)
+	server := newS3StandIn(g)
+	defer server.Close()
+
+	backend, err := NewS3(S3Options{
+		Endpoint:        server.URL,
+		Region:          "eu-west-1",
+		Bucket:          "backups",
+		Prefix:          "trashed",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	testBackend(g, backend)
+}
+
+func TestOpen(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	backend, err := Open("file:///var/lib/trashed-resources")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(backend).To(BeAssignableToTypeOf(&Filesystem{}))
+
+	backend, err = Open("s3://backups/trashed?endpoint=http://minio:9000&region=eu-west-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	s3, ok := backend.(*S3)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(s3.options.Bucket).To(Equal("backups"))
+	g.Expect(s3.options.Prefix).To(Equal("trashed/"))
+	g.Expect(s3.endpoint.Host).To(Equal("minio:9000"))
+	g.Expect(s3.options.AccessKeyID).To(Equal("access"))
+
+	for _, rawURL := range []string{"", "file://relative", "s3:///prefix", "ftp://host/path"} {
+		_, err := Open(rawURL)
+		g.Expect(err).To(HaveOccurred(), rawURL)
+		g.Expect(strings.Contains(err.Error(), "invalid storage backend")).To(BeTrue(), rawURL)
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// DefaultPrivilegedGroup is always privileged, besides the groups of privilegedGroups.
+const DefaultPrivilegedGroup = "system:masters"
+
+// ControllerServiceAccount is the user of the controller in the admission requests, the service account
+// deployed by config/default.
+const ControllerServiceAccount = "system:serviceaccount:" + ControllerNamespace + ":trashed-resources-controller-manager"
+
+const (
+	// CaptureModeWatch captures the objects from the delete and update events of the informers.
+	CaptureModeWatch = "watch"
+	// CaptureModeWebhook captures the objects in the admission webhook, before they are deleted or updated.
+	CaptureModeWebhook = "webhook"
+)
+
+const (
+	// CaptureFailureIgnore allows the operation when the admission webhook fails to capture the object.
+	CaptureFailureIgnore = "Ignore"
+	// CaptureFailureFail denies the operation when the admission webhook fails to capture the object.
+	CaptureFailureFail = "Fail"
+)
+
+// GetPrivilegedGroupsFromConfigMap parses privilegedGroups, the groups allowed to shorten the keepUntil of a
+// TrashedResource, separated by ";". Group names can have spaces.
+func GetPrivilegedGroupsFromConfigMap(configMapData v1.ConfigMap) []string {
+	groups := []string{}
+	for group := range strings.SplitSeq(configMapData.Data["privilegedGroups"], ";") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// IsPrivileged reports whether any of the groups is DefaultPrivilegedGroup or one of PrivilegedGroups.
+func (config WatchConfig) IsPrivileged(groups []string) bool {
+	for _, group := range groups {
+		if group == DefaultPrivilegedGroup || slices.Contains(config.PrivilegedGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCaptureModeFromConfigMap parses captureMode, watch or webhook. Defaults to watch.
+func GetCaptureModeFromConfigMap(configMapData v1.ConfigMap) string {
+	mode := strings.ToLower(strings.TrimSpace(configMapData.Data["captureMode"]))
+	switch mode {
+	case "":
+		return CaptureModeWatch
+	case CaptureModeWatch, CaptureModeWebhook:
+		return mode
+	}
+	logger.Error(fmt.Errorf("expected %s or %s", CaptureModeWatch, CaptureModeWebhook),
+		"Invalid captureMode in ConfigMap, using watch", "captureMode", mode)
+	return CaptureModeWatch
+}
+
+// GetCaptureFailurePolicyFromConfigMap parses captureFailurePolicy, Ignore (fail-open) or Fail
+// (fail-closed). Defaults to Ignore.
+func GetCaptureFailurePolicyFromConfigMap(configMapData v1.ConfigMap) string {
+	policy := strings.TrimSpace(configMapData.Data["captureFailurePolicy"])
+	switch {
+	case policy == "":
+		return CaptureFailureIgnore
+	case strings.EqualFold(policy, CaptureFailureIgnore):
+		return CaptureFailureIgnore
+	case strings.EqualFold(policy, CaptureFailureFail):
+		return CaptureFailureFail
+	}
+	logger.Error(fmt.Errorf("expected %s or %s", CaptureFailureIgnore, CaptureFailureFail),
+		"Invalid captureFailurePolicy in ConfigMap, using Ignore", "captureFailurePolicy", policy)
+	return CaptureFailureIgnore
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetPrivilegedGroupsFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{Data: map[string]string{"privilegedGroups": " trash-admins ; Platform Team;; "}}
+	g.Expect(GetPrivilegedGroupsFromConfigMap(cm)).To(Equal([]string{"trash-admins", "Platform Team"}))
+	g.Expect(GetPrivilegedGroupsFromConfigMap(v1.ConfigMap{})).To(BeEmpty())
+}
+
+func TestWatchConfig_IsPrivileged(t *testing.T) {
+	g := NewWithT(t)
+	config := WatchConfig{PrivilegedGroups: []string{"trash-admins"}}
+
+	g.Expect(config.IsPrivileged([]string{"system:authenticated", "trash-admins"})).To(BeTrue())
+	g.Expect(config.IsPrivileged([]string{DefaultPrivilegedGroup})).To(BeTrue())
+	g.Expect(WatchConfig{}.IsPrivileged([]string{DefaultPrivilegedGroup})).To(BeTrue())
+	g.Expect(config.IsPrivileged([]string{"system:authenticated"})).To(BeFalse())
+	g.Expect(config.IsPrivileged(nil)).To(BeFalse())
+}
+
+func TestGetCaptureModeFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GetCaptureModeFromConfigMap(v1.ConfigMap{})).To(Equal(CaptureModeWatch))
+	g.Expect(GetCaptureModeFromConfigMap(v1.ConfigMap{Data: map[string]string{"captureMode": " Webhook "}})).
+		To(Equal(CaptureModeWebhook))
+	g.Expect(GetCaptureModeFromConfigMap(v1.ConfigMap{Data: map[string]string{"captureMode": "poll"}})).
+		To(Equal(CaptureModeWatch))
+}
+
+func TestGetCaptureFailurePolicyFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GetCaptureFailurePolicyFromConfigMap(v1.ConfigMap{})).To(Equal(CaptureFailureIgnore))
+	g.Expect(GetCaptureFailurePolicyFromConfigMap(v1.ConfigMap{Data: map[string]string{"captureFailurePolicy": "fail"}})).
+		To(Equal(CaptureFailureFail))
+	g.Expect(GetCaptureFailurePolicyFromConfigMap(v1.ConfigMap{Data: map[string]string{"captureFailurePolicy": "open"}})).
+		To(Equal(CaptureFailureIgnore))
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ArchiveFormatYAML archives each expired TrashedResource as a YAML file.
+	ArchiveFormatYAML = "yaml"
+	// ArchiveFormatNDJSON archives each expired TrashedResource as a single line JSON file.
+	ArchiveFormatNDJSON = "ndjson"
+)
+
+// GetArchiveSinkFromConfigMap returns the URL where the expired TrashedResources are archived before they
+// are deleted, eg. file:///archive or s3://bucket/prefix. Empty disables the archive.
+func GetArchiveSinkFromConfigMap(configMapData v1.ConfigMap) string {
+	return strings.TrimSpace(configMapData.Data["archiveSink"])
+}
+
+// GetArchiveFormatFromConfigMap parses archiveFormat, yaml or ndjson. Defaults to yaml.
+func GetArchiveFormatFromConfigMap(configMapData v1.ConfigMap) string {
+	format := strings.ToLower(strings.TrimSpace(configMapData.Data["archiveFormat"]))
+	switch format {
+	case "":
+		return ArchiveFormatYAML
+	case ArchiveFormatYAML, ArchiveFormatNDJSON:
+		return format
+	}
+	logger.Error(fmt.Errorf("expected %s or %s", ArchiveFormatYAML, ArchiveFormatNDJSON),
+		"Invalid archiveFormat in ConfigMap, using yaml", "archiveFormat", format)
+	return ArchiveFormatYAML
+}
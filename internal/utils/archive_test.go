@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetArchiveFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{Data: map[string]string{"archiveSink": " file:///archive ", "archiveFormat": " NDJSON "}}
+	g.Expect(GetArchiveSinkFromConfigMap(cm)).To(Equal("file:///archive"))
+	g.Expect(GetArchiveFormatFromConfigMap(cm)).To(Equal(ArchiveFormatNDJSON))
+
+	g.Expect(GetArchiveSinkFromConfigMap(v1.ConfigMap{})).To(BeEmpty())
+	g.Expect(GetArchiveFormatFromConfigMap(v1.ConfigMap{})).To(Equal(ArchiveFormatYAML))
+	invalid := v1.ConfigMap{Data: map[string]string{"archiveFormat": "csv"}}
+	g.Expect(GetArchiveFormatFromConfigMap(invalid)).To(Equal(ArchiveFormatYAML))
+}
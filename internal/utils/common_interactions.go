@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"time"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
 
 	"go.yaml.in/yaml/v2"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -38,6 +41,15 @@ func getDefaultConfigData() map[string]string {
 	}
 }
 
+// GetDefaultConfigMap returns the config ConfigMap filled with the default values,
+// used when the ConfigMap does not exist.
+func GetDefaultConfigMap(cmName string) v1.ConfigMap {
+	return v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: ControllerNamespace},
+		Data:       getDefaultConfigData(),
+	}
+}
+
 var GetAllConfigsFromConfigMap = func(mgr ctrl.Manager, cmName string) v1.ConfigMap {
 	var cm v1.ConfigMap
 	logger.Info("Loading configMap", "name", cmName, "namespace", ControllerNamespace)
@@ -52,30 +64,41 @@ var GetAllConfigsFromConfigMap = func(mgr ctrl.Manager, cmName string) v1.Config
 }
 
 func GetTimetoKeepFromConfigMap(resourceReconciler *TRReconciler) string {
+	config := (*TrashedResourceReconciler)(resourceReconciler).CurrentConfig()
+
+	return Now().Add(GetDurationToKeepFromConfig(config)).ToString()
+}
+
+// GetDurationToKeepFromConfig returns the retention defined by minutesToKeep, hoursToKeep and daysToKeep.
+func GetDurationToKeepFromConfig(config WatchConfig) time.Duration {
 	minutesToKeep := int64(60)         // default value
 	hoursToKeep := int64(0)            // default value
 	daysToKeepInHours := int64(0) * 24 // default value is 0, but converted to hours
-	dateNow := Now()
 
-	if mtk, err := strconv.Atoi(resourceReconciler.MinutesToKeep); err == nil {
+	if mtk, err := strconv.Atoi(config.MinutesToKeep); err == nil {
 		minutesToKeep = int64(mtk)
 	} else {
-		logger.Error(err, "Invalid value for minutesToKeep in ConfigMap, using default", "minutesToKeep", resourceReconciler.MinutesToKeep)
+		logger.Error(err, "Invalid value for minutesToKeep in ConfigMap, using default", "minutesToKeep", config.MinutesToKeep)
 	}
 
-	if htk, err := strconv.Atoi(resourceReconciler.HoursToKeep); err == nil {
+	if htk, err := strconv.Atoi(config.HoursToKeep); err == nil {
 		hoursToKeep = int64(htk)
 	} else {
-		logger.Error(err, "Invalid value for hoursToKeep in ConfigMap, using default", "hoursToKeep", resourceReconciler.HoursToKeep)
+		logger.Error(err, "Invalid value for hoursToKeep in ConfigMap, using default", "hoursToKeep", config.HoursToKeep)
 	}
 
-	if dtk, err := strconv.Atoi(resourceReconciler.DaysToKeep); err == nil {
+	if dtk, err := strconv.Atoi(config.DaysToKeep); err == nil {
 		daysToKeepInHours = int64(dtk) * 24 // Convert days to hours
 	} else {
-		logger.Error(err, "Invalid value for daysToKeep in ConfigMap, using default", "daysToKeep", resourceReconciler.DaysToKeep)
+		logger.Error(err, "Invalid value for daysToKeep in ConfigMap, using default", "daysToKeep", config.DaysToKeep)
 	}
 
-	return dateNow.AddMinutes(minutesToKeep).AddHours(hoursToKeep).AddHours(daysToKeepInHours).ToString()
+	return time.Duration(minutesToKeep)*time.Minute + time.Duration(hoursToKeep+daysToKeepInHours)*time.Hour
+}
+
+// GetDurationToKeep returns the retention defined by a TrashedResourcePolicy.
+func GetDurationToKeep(retention moxv1alpha1.Retention) time.Duration {
+	return time.Duration(retention.Minutes)*time.Minute + time.Duration(retention.Hours+retention.Days*24)*time.Hour
 }
 
 func MakeBodyManifest(kubernetesObj client.Object) []byte {
@@ -3,6 +3,7 @@ package utils
 import (
 	"testing"
 	"time"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
 
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
@@ -84,11 +85,10 @@ func TestMakeBodyManifest_InfersAPIVersion(t *testing.T) {
 func TestGetTimetoKeepFromConfigMap(t *testing.T) {
 	g := NewWithT(t)
 
-	reconciler := &TRReconciler{
-		MinutesToKeep: "10",
-		HoursToKeep:   "1",
-		DaysToKeep:    "0",
-	}
+	reconciler := &TRReconciler{}
+	(*TrashedResourceReconciler)(reconciler).UpdateConfig(func(config *WatchConfig) {
+		*config = WatchConfig{MinutesToKeep: "10", HoursToKeep: "1", DaysToKeep: "0"}
+	})
 
 	keepUntil := GetTimetoKeepFromConfigMap(reconciler)
 	g.Expect(keepUntil).NotTo(BeEmpty())
@@ -100,3 +100,14 @@ func TestGetTimetoKeepFromConfigMap(t *testing.T) {
 	// It should be in the future (1h 10m from now)
 	g.Expect(parsedTime.After(time.Now())).To(BeTrue())
 }
+
+func TestGetDurationToKeep(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GetDurationToKeep(moxv1alpha1.Retention{Minutes: 30, Hours: 1, Days: 2})).
+		To(Equal(30*time.Minute + 49*time.Hour))
+	g.Expect(GetDurationToKeepFromConfig(WatchConfig{MinutesToKeep: "10", HoursToKeep: "1", DaysToKeep: "1"})).
+		To(Equal(10*time.Minute + 25*time.Hour))
+	// Invalid values use the defaults
+	g.Expect(GetDurationToKeepFromConfig(WatchConfig{})).To(Equal(60 * time.Minute))
+}
@@ -1,19 +1,141 @@
 package utils
 
 import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type TrashedResourceReconciler struct {
 	client.Client
-	Scheme             *runtime.Scheme
-	Config             v1.ConfigMap
+	Scheme *runtime.Scheme
+	// Recorder emits the Events of the captured objects and of the TrashedResources, it can be nil.
+	Recorder events.EventRecorder
+
+	// configMu guards config, loaded from the ConfigMap and the policies, which can be reloaded at runtime.
+	// It is only read with CurrentConfig.
+	configMu sync.RWMutex
+	config   WatchConfig
+}
+
+// WatchConfig is a point in time copy of the configuration loaded from the ConfigMap.
+type WatchConfig struct {
 	KindsToWatch       []string
 	ActionsToWatch     []string
 	NamespacesToIgnore []string
 	MinutesToKeep      string
 	HoursToKeep        string
 	DaysToKeep         string
+	// RetentionByKind and RetentionByNamespace override the retention, kinds are in lowercase.
+	RetentionByKind      map[string]time.Duration
+	RetentionByNamespace map[string]time.Duration
+	Policies             []moxv1alpha1.TrashedResourcePolicy
+	// DataProtectionByKind is the data protection of each kind, in lowercase, and EncryptionKeySecret the
+	// Secret with the key used to encrypt.
+	DataProtectionByKind map[string]string
+	EncryptionKeySecret  string
+	// CompressAbove and MaxInlineSize are sizes in bytes of the captured objects, StorageBackend the URL
+	// where the objects larger than MaxInlineSize are stored.
+	CompressAbove  int64
+	MaxInlineSize  int64
+	StorageBackend string
+	// ArchiveSink is the URL where the expired TrashedResources are archived in ArchiveFormat.
+	ArchiveSink   string
+	ArchiveFormat string
+	// PrivilegedGroups can shorten the keepUntil of the TrashedResources.
+	PrivilegedGroups []string
+	// CaptureMode is where the objects are captured, CaptureFailurePolicy whether the admission webhook
+	// denies the operations of the objects it fails to capture.
+	CaptureMode          string
+	CaptureFailurePolicy string
+	// OfflineIndex is the URL where the last known state of the watched objects is written every
+	// OfflineIndexInterval.
+	OfflineIndex         string
+	OfflineIndexInterval time.Duration
+	// FinalizerKinds are captured with the mox.app.br/trash-protection finalizer.
+	FinalizerKinds []string
+	// VaultNamespace holds the TrashedResources of every namespace when it is set.
+	VaultNamespace string
+}
+
+// ApplyConfigMap loads every configuration value from configMapData into the reconciler, keeping the policies.
+func (r *TrashedResourceReconciler) ApplyConfigMap(configMapData v1.ConfigMap) {
+	r.UpdateConfig(func(config *WatchConfig) {
+		*config = WatchConfig{
+			KindsToWatch:         GetKindsToWatchFromConfigMap(configMapData),
+			ActionsToWatch:       GetActionsToWatchFromConfigMap(configMapData),
+			NamespacesToIgnore:   GetNamespacesToIgnoreFromConfigMap(configMapData),
+			MinutesToKeep:        GetMinutesToKeepFromConfigMap(configMapData),
+			HoursToKeep:          GetHoursToKeepFromConfigMap(configMapData),
+			DaysToKeep:           GetDaysToKeepFromConfigMap(configMapData),
+			RetentionByKind:      GetRetentionByKindFromConfigMap(configMapData),
+			RetentionByNamespace: GetRetentionByNamespaceFromConfigMap(configMapData),
+			Policies:             config.Policies,
+			DataProtectionByKind: GetDataProtectionByKindFromConfigMap(configMapData),
+			EncryptionKeySecret:  GetEncryptionKeySecretFromConfigMap(configMapData),
+			CompressAbove:        GetCompressAboveFromConfigMap(configMapData),
+			MaxInlineSize:        GetMaxInlineSizeFromConfigMap(configMapData),
+			StorageBackend:       GetStorageBackendFromConfigMap(configMapData),
+			ArchiveSink:          GetArchiveSinkFromConfigMap(configMapData),
+			ArchiveFormat:        GetArchiveFormatFromConfigMap(configMapData),
+			PrivilegedGroups:     GetPrivilegedGroupsFromConfigMap(configMapData),
+			CaptureMode:          GetCaptureModeFromConfigMap(configMapData),
+			CaptureFailurePolicy: GetCaptureFailurePolicyFromConfigMap(configMapData),
+			OfflineIndex:         GetOfflineIndexFromConfigMap(configMapData),
+			OfflineIndexInterval: GetOfflineIndexIntervalFromConfigMap(configMapData),
+			FinalizerKinds:       GetFinalizerKindsFromConfigMap(configMapData),
+			VaultNamespace:       GetVaultNamespaceFromConfigMap(configMapData),
+		}
+	})
+}
+
+// UpdateConfig changes the configuration while holding the lock, eg. to set it without a ConfigMap.
+func (r *TrashedResourceReconciler) UpdateConfig(update func(config *WatchConfig)) {
+	r.configMu.Lock()
+	defer r.configMu.Unlock()
+
+	update(&r.config)
+}
+
+// CurrentConfig returns a copy of the loaded configuration, safe to use while it is being reloaded.
+func (r *TrashedResourceReconciler) CurrentConfig() WatchConfig {
+	r.configMu.RLock()
+	defer r.configMu.RUnlock()
+
+	config := r.config
+	config.KindsToWatch = slices.Clone(config.KindsToWatch)
+	config.ActionsToWatch = slices.Clone(config.ActionsToWatch)
+	config.NamespacesToIgnore = slices.Clone(config.NamespacesToIgnore)
+	config.RetentionByKind = maps.Clone(config.RetentionByKind)
+	config.RetentionByNamespace = maps.Clone(config.RetentionByNamespace)
+	config.Policies = slices.Clone(config.Policies)
+	config.DataProtectionByKind = maps.Clone(config.DataProtectionByKind)
+	config.PrivilegedGroups = slices.Clone(config.PrivilegedGroups)
+	config.FinalizerKinds = slices.Clone(config.FinalizerKinds)
+	return config
+}
+
+// ApplyPolicies replaces the TrashedResourcePolicies used to capture objects.
+func (r *TrashedResourceReconciler) ApplyPolicies(policies []moxv1alpha1.TrashedResourcePolicy) {
+	r.UpdateConfig(func(config *WatchConfig) {
+		config.Policies = policies
+	})
+}
+
+// AllKindsToWatch returns the kinds of the ConfigMap followed by the kinds of every policy.
+func (config WatchConfig) AllKindsToWatch() []string {
+	kinds := slices.Clone(config.KindsToWatch)
+	for _, policy := range config.Policies {
+		for _, kind := range policy.Spec.Kinds {
+			kinds = append(kinds, kind.String())
+		}
+	}
+	return kinds
 }
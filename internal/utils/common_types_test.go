@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	moxv1alpha1 "trashed-resources/api/v1alpha1"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestApplyConfigMap(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := &TrashedResourceReconciler{}
+
+	reconciler.ApplyConfigMap(v1.ConfigMap{Data: map[string]string{
+		"kindsToObserve":     "Deployment; Secret",
+		"actionsToObserve":   "delete; update",
+		"namespacesToIgnore": "kube-system",
+		"minutesToKeep":      "10",
+		"hoursToKeep":        "1",
+		"daysToKeep":         "2",
+	}})
+
+	config := reconciler.CurrentConfig()
+	g.Expect(config.KindsToWatch).To(Equal([]string{"Deployment", "Secret"}))
+	g.Expect(config.ActionsToWatch).To(Equal([]string{"delete", "update"}))
+	g.Expect(config.NamespacesToIgnore).To(Equal([]string{"kube-system"}))
+	g.Expect(config.MinutesToKeep).To(Equal("10"))
+	g.Expect(config.HoursToKeep).To(Equal("1"))
+	g.Expect(config.DaysToKeep).To(Equal("2"))
+
+	// The returned copy must not change the reconciler configuration
+	config.KindsToWatch[0] = "Pod"
+	g.Expect(reconciler.CurrentConfig().KindsToWatch).To(Equal([]string{"Deployment", "Secret"}))
+
+	// The policies are kept when the ConfigMap is reloaded
+	reconciler.ApplyPolicies([]moxv1alpha1.TrashedResourcePolicy{{}})
+	reconciler.ApplyConfigMap(v1.ConfigMap{Data: map[string]string{"kindsToObserve": "ConfigMap"}})
+	g.Expect(reconciler.CurrentConfig().KindsToWatch).To(Equal([]string{"ConfigMap"}))
+	g.Expect(reconciler.CurrentConfig().Policies).To(HaveLen(1))
+}
+
+func TestApplyConfigMap_ConcurrentAccess(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := &TrashedResourceReconciler{}
+	cm := GetDefaultConfigMap("config")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			reconciler.ApplyConfigMap(cm)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = reconciler.CurrentConfig()
+		}()
+	}
+	wg.Wait()
+
+	g.Expect(reconciler.CurrentConfig().KindsToWatch).To(Equal([]string{"Deployment", "Secret", "ConfigMap"}))
+}
+
+func TestWatchConfig_AllKindsToWatch(t *testing.T) {
+	g := NewWithT(t)
+	config := WatchConfig{
+		KindsToWatch: []string{"Deployment"},
+		Policies: []moxv1alpha1.TrashedResourcePolicy{{
+			Spec: moxv1alpha1.TrashedResourcePolicySpec{
+				Kinds: []moxv1alpha1.PolicyKind{
+					{Kind: "Secret", Version: "v1"},
+					{Group: "cert-manager.io", Kind: "Certificate"},
+				},
+			},
+		}},
+	}
+
+	g.Expect(config.AllKindsToWatch()).To(Equal([]string{"Deployment", "Secret/v1", "Certificate.cert-manager.io"}))
+}
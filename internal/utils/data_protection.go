@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Values of dataProtectionByKind.
+const (
+	// DataProtectionNone stores the captured object as it is.
+	DataProtectionNone = "none"
+	// DataProtectionRedact drops the values of data, stringData and binaryData.
+	DataProtectionRedact = "redact"
+	// DataProtectionEncrypt encrypts the captured object with the key of encryptionKeySecret.
+	DataProtectionEncrypt = "encrypt"
+	// DataProtectionSecret stores the captured object in a Secret owned by the TrashedResource.
+	DataProtectionSecret = "secret"
+)
+
+// EncryptionKeySecretKey is the key of encryptionKeySecret that holds the 32 bytes encryption key.
+const EncryptionKeySecretKey = "key"
+
+// GetDataProtectionByKindFromConfigMap parses dataProtectionByKind, eg. "Secret=encrypt; ConfigMap=redact".
+// The kinds are stored in lowercase.
+func GetDataProtectionByKindFromConfigMap(configMapData v1.ConfigMap) map[string]string {
+	modes := []string{DataProtectionNone, DataProtectionRedact, DataProtectionEncrypt, DataProtectionSecret}
+	rules := map[string]string{}
+	for _, rawRule := range strings.Split(configMapData.Data["dataProtectionByKind"], ";") {
+		if strings.TrimSpace(rawRule) == "" {
+			continue
+		}
+		kind, mode, found := strings.Cut(rawRule, "=")
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		mode = strings.ToLower(strings.TrimSpace(mode))
+		if !found || kind == "" || !slices.Contains(modes, mode) {
+			logger.Error(fmt.Errorf("expected kind=%s", strings.Join(modes, "|")),
+				"Invalid data protection rule in ConfigMap, ignoring", "dataProtectionByKind", rawRule)
+			continue
+		}
+		rules[kind] = mode
+	}
+	return rules
+}
+
+// GetEncryptionKeySecretFromConfigMap returns the name of the Secret, in the ControllerNamespace, with the key
+// used by the encrypt data protection.
+func GetEncryptionKeySecretFromConfigMap(configMapData v1.ConfigMap) string {
+	return strings.TrimSpace(configMapData.Data["encryptionKeySecret"])
+}
+
+// DataProtectionOf returns the data protection of the kind. Secrets are stored in a Secret owned by the
+// TrashedResource unless another mode is configured, so their values are not readable by everyone who can
+// read TrashedResources.
+func (config WatchConfig) DataProtectionOf(kind string) string {
+	if mode, found := config.DataProtectionByKind[strings.ToLower(kind)]; found {
+		return mode
+	}
+	if strings.EqualFold(kind, "Secret") {
+		return DataProtectionSecret
+	}
+	return DataProtectionNone
+}
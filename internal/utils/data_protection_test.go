@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetDataProtectionByKindFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{
+		Data: map[string]string{
+			"dataProtectionByKind": "Secret=encrypt; ConfigMap = Redact; Deployment=invalid; =none;",
+			"encryptionKeySecret":  " trashed-resources-key ",
+		},
+	}
+
+	g.Expect(GetDataProtectionByKindFromConfigMap(cm)).To(Equal(map[string]string{
+		"secret":    DataProtectionEncrypt,
+		"configmap": DataProtectionRedact,
+	}))
+	g.Expect(GetEncryptionKeySecretFromConfigMap(cm)).To(Equal("trashed-resources-key"))
+}
+
+func TestWatchConfig_DataProtectionOf(t *testing.T) {
+	g := NewWithT(t)
+
+	config := WatchConfig{}
+	g.Expect(config.DataProtectionOf("Secret")).To(Equal(DataProtectionSecret))
+	g.Expect(config.DataProtectionOf("ConfigMap")).To(Equal(DataProtectionNone))
+
+	config.DataProtectionByKind = map[string]string{"secret": DataProtectionNone, "configmap": DataProtectionRedact}
+	g.Expect(config.DataProtectionOf("Secret")).To(Equal(DataProtectionNone))
+	g.Expect(config.DataProtectionOf("ConfigMap")).To(Equal(DataProtectionRedact))
+}
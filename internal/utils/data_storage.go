@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DefaultCompressAbove is the size of the captured objects above which they are compressed.
+	DefaultCompressAbove = 256 * 1024
+	// DefaultMaxInlineSize is the size of the encoded objects above which they are moved to the storage
+	// backend, under the ~1.5MiB limit of an object in etcd.
+	DefaultMaxInlineSize = 1024 * 1024
+)
+
+// GetCompressAboveFromConfigMap parses compressAbove, a size like 256Ki.
+func GetCompressAboveFromConfigMap(configMapData v1.ConfigMap) int64 {
+	return parseSize(configMapData.Data["compressAbove"], "compressAbove", DefaultCompressAbove)
+}
+
+// GetMaxInlineSizeFromConfigMap parses maxInlineSize, a size like 1Mi.
+func GetMaxInlineSizeFromConfigMap(configMapData v1.ConfigMap) int64 {
+	return parseSize(configMapData.Data["maxInlineSize"], "maxInlineSize", DefaultMaxInlineSize)
+}
+
+// GetStorageBackendFromConfigMap returns the URL of the storage backend, eg. s3://bucket/prefix.
+func GetStorageBackendFromConfigMap(configMapData v1.ConfigMap) string {
+	return strings.TrimSpace(configMapData.Data["storageBackend"])
+}
+
+func parseSize(value, key string, defaultSize int64) int64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultSize
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil || quantity.Sign() <= 0 {
+		logger.Error(fmt.Errorf("expected a size like 256Ki or 1Mi"), "Invalid size in ConfigMap, using default", key, value)
+		return defaultSize
+	}
+	return quantity.Value()
+}
+
+// CompressionThreshold returns CompressAbove, or DefaultCompressAbove when it is not set.
+func (config WatchConfig) CompressionThreshold() int64 {
+	if config.CompressAbove <= 0 {
+		return DefaultCompressAbove
+	}
+	return config.CompressAbove
+}
+
+// InlineLimit returns MaxInlineSize, or DefaultMaxInlineSize when it is not set.
+func (config WatchConfig) InlineLimit() int64 {
+	if config.MaxInlineSize <= 0 {
+		return DefaultMaxInlineSize
+	}
+	return config.MaxInlineSize
+}
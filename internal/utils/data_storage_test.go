@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetDataStorageFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{Data: map[string]string{
+		"compressAbove":  "64Ki",
+		"maxInlineSize":  " 500000 ",
+		"storageBackend": " s3://backups/trashed ",
+	}}
+	g.Expect(GetCompressAboveFromConfigMap(cm)).To(Equal(int64(64 * 1024)))
+	g.Expect(GetMaxInlineSizeFromConfigMap(cm)).To(Equal(int64(500000)))
+	g.Expect(GetStorageBackendFromConfigMap(cm)).To(Equal("s3://backups/trashed"))
+
+	invalid := v1.ConfigMap{Data: map[string]string{"compressAbove": "big", "maxInlineSize": "-1Mi"}}
+	g.Expect(GetCompressAboveFromConfigMap(invalid)).To(Equal(int64(DefaultCompressAbove)))
+	g.Expect(GetMaxInlineSizeFromConfigMap(invalid)).To(Equal(int64(DefaultMaxInlineSize)))
+	g.Expect(GetStorageBackendFromConfigMap(invalid)).To(BeEmpty())
+}
+
+func TestWatchConfig_DataStorageDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(WatchConfig{}.CompressionThreshold()).To(Equal(int64(DefaultCompressAbove)))
+	g.Expect(WatchConfig{}.InlineLimit()).To(Equal(int64(DefaultMaxInlineSize)))
+	g.Expect(WatchConfig{CompressAbove: 10, MaxInlineSize: 20}.CompressionThreshold()).To(Equal(int64(10)))
+	g.Expect(WatchConfig{CompressAbove: 10, MaxInlineSize: 20}.InlineLimit()).To(Equal(int64(20)))
+}
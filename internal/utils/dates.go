@@ -11,9 +11,17 @@ type DateTime struct {
 	Time time.Time `json:"time"`
 }
 
-var GetTimeRemaining = func(futureDateToCompare string) time.Duration {
-	parsedTime, _ := time.Parse(timeFormat, futureDateToCompare)
-	return parsedTime.Sub(Now().Time)
+var GetTimeRemaining = func(futureDateToCompare time.Time) time.Duration {
+	return futureDateToCompare.Sub(Now().Time)
+}
+
+// ParseDateTime parses a RFC3339 date.
+func ParseDateTime(value string) (DateTime, error) {
+	parsedTime, err := time.Parse(timeFormat, value)
+	if err != nil {
+		return DateTime{}, err
+	}
+	return DateTime{Time: parsedTime}, nil
 }
 
 // Now initializes DateTime with the current time
@@ -32,14 +40,23 @@ func (dt DateTime) AddMinutes(minutes int64) DateTime {
 	return DateTime{Time: dt.Time.Add(time.Duration(minutes) * time.Minute)}
 }
 
+// Add adds a duration to the current DateTime
+func (dt DateTime) Add(duration time.Duration) DateTime {
+	return DateTime{Time: dt.Time.Add(duration)}
+}
+
 // AddSeconds adds seconds to the current DateTime
 func (dt DateTime) AddSeconds(seconds int64) DateTime {
 	return DateTime{Time: dt.Time.Add(time.Duration(seconds) * time.Second)}
 }
 
+// NowIsAfterOrEqualCompareDate reports whether the date was reached. An invalid date is never reached.
 func NowIsAfterOrEqualCompareDate(dateToCompare string) bool {
-	parsedTime, _ := time.Parse(timeFormat, dateToCompare)
-	return !Now().Time.Before(parsedTime)
+	parsed, err := ParseDateTime(dateToCompare)
+	if err != nil {
+		return false
+	}
+	return !Now().Time.Before(parsed.Time)
 }
 
 // ToString returns the default string representation
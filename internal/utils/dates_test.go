@@ -57,17 +57,15 @@ func TestGetTimeRemaining(t *testing.T) {
 	// Teste com data futura
 	nowDate := time.Now()
 	future := nowDate.Add(1 * time.Hour)
-	futureStr := future.Format(time.RFC3339)
 
-	remaining := GetTimeRemaining(futureStr)
+	remaining := GetTimeRemaining(future)
 	// Deve ser aproximadamente 1 hora (margem de 5s para execução)
 	g.Expect(remaining).To(BeNumerically("~", time.Hour, 5*time.Second))
 
 	// Teste com data passada
 	past := time.Now().Add(-1 * time.Hour)
-	pastStr := past.Format(time.RFC3339)
 
-	remainingPast := GetTimeRemaining(pastStr)
+	remainingPast := GetTimeRemaining(past)
 	g.Expect(remainingPast).To(BeNumerically("~", -time.Hour, 5*time.Second))
 }
 
@@ -81,4 +79,18 @@ func TestNowIsAfterOrEqualCompareDate(t *testing.T) {
 	// Data futura: Agora é ANTES da data -> False
 	futureDate := time.Now().Add(1 * time.Hour).Format(time.RFC3339)
 	g.Expect(NowIsAfterOrEqualCompareDate(futureDate)).To(BeFalse())
+
+	// Data inválida nunca é atingida
+	g.Expect(NowIsAfterOrEqualCompareDate("tomorrow")).To(BeFalse())
+}
+
+func TestParseDateTime(t *testing.T) {
+	g := NewWithT(t)
+
+	parsed, err := ParseDateTime("2024-01-01T12:30:45Z")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(parsed.Time).To(Equal(time.Date(2024, 1, 1, 12, 30, 45, 0, time.UTC)))
+
+	_, err = ParseDateTime("2024-01-01 12:30:45")
+	g.Expect(err).To(HaveOccurred())
 }
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// DefaultOfflineIndexInterval is how often the offline index is written.
+const DefaultOfflineIndexInterval = 5 * time.Minute
+
+// GetOfflineIndexFromConfigMap returns the URL where the last known state of the watched objects is kept,
+// eg. file:///var/lib/trashed-resources or s3://bucket/prefix. Empty disables the offline index.
+func GetOfflineIndexFromConfigMap(configMapData v1.ConfigMap) string {
+	return strings.TrimSpace(configMapData.Data["offlineIndex"])
+}
+
+// GetOfflineIndexIntervalFromConfigMap parses offlineIndexInterval, a duration like 5m.
+func GetOfflineIndexIntervalFromConfigMap(configMapData v1.ConfigMap) time.Duration {
+	value := strings.TrimSpace(configMapData.Data["offlineIndexInterval"])
+	if value == "" {
+		return DefaultOfflineIndexInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		logger.Error(fmt.Errorf("expected a duration like 5m"), "Invalid offlineIndexInterval in ConfigMap, using default",
+			"offlineIndexInterval", value)
+		return DefaultOfflineIndexInterval
+	}
+	return interval
+}
+
+// IndexInterval returns OfflineIndexInterval, or DefaultOfflineIndexInterval when it is not set.
+func (config WatchConfig) IndexInterval() time.Duration {
+	if config.OfflineIndexInterval <= 0 {
+		return DefaultOfflineIndexInterval
+	}
+	return config.OfflineIndexInterval
+}
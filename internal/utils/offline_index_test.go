@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetOfflineIndexFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{Data: map[string]string{
+		"offlineIndex":         " file:///var/lib/trashed-resources ",
+		"offlineIndexInterval": "90s",
+	}}
+	g.Expect(GetOfflineIndexFromConfigMap(cm)).To(Equal("file:///var/lib/trashed-resources"))
+	g.Expect(GetOfflineIndexIntervalFromConfigMap(cm)).To(Equal(90 * time.Second))
+
+	g.Expect(GetOfflineIndexFromConfigMap(v1.ConfigMap{})).To(BeEmpty())
+	g.Expect(GetOfflineIndexIntervalFromConfigMap(v1.ConfigMap{})).To(Equal(DefaultOfflineIndexInterval))
+	g.Expect(GetOfflineIndexIntervalFromConfigMap(v1.ConfigMap{Data: map[string]string{"offlineIndexInterval": "-1m"}})).
+		To(Equal(DefaultOfflineIndexInterval))
+	g.Expect(WatchConfig{}.IndexInterval()).To(Equal(DefaultOfflineIndexInterval))
+}
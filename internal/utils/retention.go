@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// RetentionAnnotation overrides, on a namespace, the retention of every object captured in it (eg. 7d, 12h).
+	RetentionAnnotation = "mox.app.br/retention"
+	// RetentionRuleAnnotation records on the TrashedResource which rule defined its retention.
+	RetentionRuleAnnotation = "mox.app.br/retention-rule"
+)
+
+var retentionDaysRegex = regexp.MustCompile(`^(\d+)d(.*)$`)
+
+// ParseRetention parses a retention as a Go duration that can also start with days, eg. 30d, 2d12h, 90m.
+func ParseRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	days := int64(0)
+	if match := retentionDaysRegex.FindStringSubmatch(value); match != nil {
+		days, _ = strconv.ParseInt(match[1], 10, 64)
+		value = match[2]
+	}
+
+	duration := time.Duration(0)
+	if value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q (use 30d, 12h, 90m): %w", value, err)
+		}
+		duration = parsed
+	}
+	duration += time.Duration(days) * 24 * time.Hour
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("retention must be greater than zero")
+	}
+	return duration, nil
+}
+
+// GetRetentionByKindFromConfigMap parses retentionByKind, eg. "Secret=30d; ConfigMap=1h".
+// The kinds are stored in lowercase.
+func GetRetentionByKindFromConfigMap(configMapData v1.ConfigMap) map[string]time.Duration {
+	return parseRetentionRules(configMapData.Data["retentionByKind"], "retentionByKind", strings.ToLower)
+}
+
+// GetRetentionByNamespaceFromConfigMap parses retentionByNamespace, eg. "audit=90d; dev=30m".
+func GetRetentionByNamespaceFromConfigMap(configMapData v1.ConfigMap) map[string]time.Duration {
+	return parseRetentionRules(configMapData.Data["retentionByNamespace"], "retentionByNamespace", strings.TrimSpace)
+}
+
+func parseRetentionRules(rawRules, key string, normalizeKey func(string) string) map[string]time.Duration {
+	rules := map[string]time.Duration{}
+	for _, rawRule := range strings.Split(rawRules, ";") {
+		if strings.TrimSpace(rawRule) == "" {
+			continue
+		}
+		name, value, found := strings.Cut(rawRule, "=")
+		name = normalizeKey(strings.TrimSpace(name))
+		if !found || name == "" {
+			logger.Error(fmt.Errorf("expected name=retention"), "Invalid retention rule in ConfigMap, ignoring", key, rawRule)
+			continue
+		}
+		duration, err := ParseRetention(value)
+		if err != nil {
+			logger.Error(err, "Invalid retention rule in ConfigMap, ignoring", key, rawRule)
+			continue
+		}
+		rules[name] = duration
+	}
+	return rules
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestParseRetention(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"2d12h", 60 * time.Hour},
+		{"1h", time.Hour},
+		{" 90m ", 90 * time.Minute},
+	}
+	for _, test := range tests {
+		duration, err := ParseRetention(test.value)
+		g.Expect(err).NotTo(HaveOccurred(), test.value)
+		g.Expect(duration).To(Equal(test.expected), test.value)
+	}
+
+	for _, value := range []string{"", "0d", "abc", "10", "-1h", "1w"} {
+		_, err := ParseRetention(value)
+		g.Expect(err).To(HaveOccurred(), value)
+	}
+}
+
+func TestGetRetentionRulesFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{
+		Data: map[string]string{
+			"retentionByKind":      "Secret=30d; ConfigMap=1h; Deployment=invalid; =2h",
+			"retentionByNamespace": "audit=90d;dev = 30m;",
+		},
+	}
+
+	g.Expect(GetRetentionByKindFromConfigMap(cm)).To(Equal(map[string]time.Duration{
+		"secret":    30 * 24 * time.Hour,
+		"configmap": time.Hour,
+	}))
+	g.Expect(GetRetentionByNamespaceFromConfigMap(cm)).To(Equal(map[string]time.Duration{
+		"audit": 90 * 24 * time.Hour,
+		"dev":   30 * time.Minute,
+	}))
+	g.Expect(GetRetentionByKindFromConfigMap(v1.ConfigMap{})).To(BeEmpty())
+}
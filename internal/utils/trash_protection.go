@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GetFinalizerKindsFromConfigMap parses finalizerKinds, the observed kinds whose objects get the
+// mox.app.br/trash-protection finalizer, in the format of kindsToObserve.
+func GetFinalizerKindsFromConfigMap(configMapData v1.ConfigMap) []string {
+	rawKinds := strings.Split(configMapData.Data["finalizerKinds"], ";")
+
+	return strings.Fields(strings.Join(rawKinds, " "))
+}
+
+// MatchesKindToWatch reports whether gvk is the kind of an entry of kindsToObserve. The group and the
+// version are only compared when the entry has them.
+func MatchesKindToWatch(rawKind string, gvk schema.GroupVersionKind) bool {
+	kind := ParseKindToWatch(rawKind)
+	return strings.EqualFold(kind.Kind, gvk.Kind) &&
+		(kind.Group == "" || kind.Group == gvk.Group) &&
+		(kind.Version == "" || kind.Version == gvk.Version)
+}
+
+// ProtectsKind reports whether the objects of gvk are captured with the mox.app.br/trash-protection finalizer.
+func (config WatchConfig) ProtectsKind(gvk schema.GroupVersionKind) bool {
+	return slices.ContainsFunc(config.FinalizerKinds, func(rawKind string) bool {
+		return MatchesKindToWatch(rawKind, gvk)
+	})
+}
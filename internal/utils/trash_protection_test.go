@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGetFinalizerKindsFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{Data: map[string]string{"finalizerKinds": " Secret;Certificate.cert-manager.io/v1 ;"}}
+	g.Expect(GetFinalizerKindsFromConfigMap(cm)).To(Equal([]string{"Secret", "Certificate.cert-manager.io/v1"}))
+	g.Expect(GetFinalizerKindsFromConfigMap(v1.ConfigMap{})).To(BeEmpty())
+}
+
+func TestWatchConfig_ProtectsKind(t *testing.T) {
+	g := NewWithT(t)
+	config := WatchConfig{FinalizerKinds: []string{"secret", "Certificate.cert-manager.io/v1"}}
+
+	g.Expect(config.ProtectsKind(schema.GroupVersionKind{Version: "v1", Kind: "Secret"})).To(BeTrue())
+	g.Expect(config.ProtectsKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})).
+		To(BeTrue())
+	g.Expect(config.ProtectsKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Certificate"})).
+		To(BeFalse())
+	g.Expect(config.ProtectsKind(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})).To(BeFalse())
+	g.Expect(WatchConfig{}.ProtectsKind(schema.GroupVersionKind{Version: "v1", Kind: "Secret"})).To(BeFalse())
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// GetVaultNamespaceFromConfigMap returns the namespace where every TrashedResource is created, so they are
+// not deleted with the namespace of the original objects. Empty keeps them in the namespace of the objects.
+func GetVaultNamespaceFromConfigMap(configMapData v1.ConfigMap) string {
+	return strings.TrimSpace(configMapData.Data["vaultNamespace"])
+}
+
+// TrashNamespace returns the namespace of the TrashedResources of the objects of namespace: the
+// VaultNamespace when it is set, otherwise namespace itself.
+func (config WatchConfig) TrashNamespace(namespace string) string {
+	if config.VaultNamespace != "" {
+		return config.VaultNamespace
+	}
+	return namespace
+}
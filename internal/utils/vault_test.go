@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetVaultNamespaceFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := v1.ConfigMap{Data: map[string]string{"vaultNamespace": " trash-vault "}}
+	g.Expect(GetVaultNamespaceFromConfigMap(cm)).To(Equal("trash-vault"))
+	g.Expect(GetVaultNamespaceFromConfigMap(v1.ConfigMap{})).To(BeEmpty())
+
+	g.Expect(WatchConfig{VaultNamespace: "trash-vault"}.TrashNamespace("team-a")).To(Equal("trash-vault"))
+	g.Expect(WatchConfig{}.TrashNamespace("team-a")).To(Equal("team-a"))
+}
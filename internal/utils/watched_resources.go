@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // ResourceGVK define a estrutura para mapear o Group e Version de um Kind
@@ -56,6 +58,58 @@ func KnownGVKsAsString() string {
 	return builder.String()
 }
 
+// ParseKindToWatch splits an entry of kindsToObserve into its kind, group and version.
+// Accepted forms are "Kind", "Kind/version" (core group), "Kind.group" and "Kind.group/version",
+// e.g. "Certificate.cert-manager.io/v1".
+func ParseKindToWatch(rawKind string) schema.GroupVersionKind {
+	gvk := schema.GroupVersionKind{}
+	kindAndGroup := strings.TrimSpace(rawKind)
+	if idx := strings.Index(kindAndGroup, "/"); idx >= 0 {
+		gvk.Version = kindAndGroup[idx+1:]
+		kindAndGroup = kindAndGroup[:idx]
+	}
+	if idx := strings.Index(kindAndGroup, "."); idx >= 0 {
+		gvk.Group = kindAndGroup[idx+1:]
+		kindAndGroup = kindAndGroup[:idx]
+	}
+	gvk.Kind = kindAndGroup
+
+	return gvk
+}
+
+// ResolveKindToWatch resolves an entry of kindsToObserve to a served GroupVersionKind using the
+// RESTMapper, so any built-in kind or CRD known by the API server can be watched.
+// Bare names of the known kinds keep resolving to their usual group.
+func ResolveKindToWatch(mapper meta.RESTMapper, rawKind string) (schema.GroupVersionKind, error) {
+	gvk := ParseKindToWatch(rawKind)
+	if gvk.Kind == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid kind %q, expected kind or kind.group/version", rawKind)
+	}
+	if rgvk, ok := knownGVKs[strings.ToLower(gvk.Kind)]; ok && gvk.Group == "" && gvk.Version == "" {
+		gvk.Group = rgvk.Group
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil {
+		return mapping.GroupVersionKind, nil
+	}
+	if !meta.IsNoMatchError(err) {
+		return schema.GroupVersionKind{}, err
+	}
+
+	// Kinds are case sensitive for the RESTMapper, retry through the lowercase singular resource name.
+	resolved, errByResource := mapper.KindFor(schema.GroupVersionResource{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Resource: strings.ToLower(gvk.Kind),
+	})
+	if errByResource != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("kind %q is not served by the cluster: %w", rawKind, err)
+	}
+
+	return resolved, nil
+}
+
 func GetKindsToWatchFromConfigMap(configMapData v1.ConfigMap) []string {
 	rawKinds := strings.Split(configMapData.Data["kindsToObserve"], ";")
 
@@ -5,6 +5,8 @@ import (
 
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestGetKnownKindsToWatch(t *testing.T) {
@@ -16,7 +18,6 @@ func TestGetKnownKindsToWatch(t *testing.T) {
 }
 
 func TestGetKindsToWatchFromConfigMap(t *testing.T) {
-	g := NewWithT(t)
 	testCases := []struct {
 		name     string
 		data     map[string]string
@@ -31,6 +32,7 @@ func TestGetKindsToWatchFromConfigMap(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
 			cm := v1.ConfigMap{Data: tc.data}
 			result := GetKindsToWatchFromConfigMap(cm)
 			if len(tc.expected) == 0 {
@@ -43,7 +45,6 @@ func TestGetKindsToWatchFromConfigMap(t *testing.T) {
 }
 
 func TestGetActionsToWatchFromConfigMap(t *testing.T) {
-	g := NewWithT(t)
 	testCases := []struct {
 		name     string
 		data     map[string]string
@@ -57,6 +58,7 @@ func TestGetActionsToWatchFromConfigMap(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
 			cm := v1.ConfigMap{Data: tc.data}
 			result := GetActionsToWatchFromConfigMap(cm)
 			if len(tc.expected) == 0 {
@@ -69,7 +71,6 @@ func TestGetActionsToWatchFromConfigMap(t *testing.T) {
 }
 
 func TestGetNamespacesToIgnoreFromConfigMap(t *testing.T) {
-	g := NewWithT(t)
 	testCases := []struct {
 		name     string
 		data     map[string]string
@@ -83,6 +84,7 @@ func TestGetNamespacesToIgnoreFromConfigMap(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
 			cm := v1.ConfigMap{Data: tc.data}
 			result := GetNamespacesToIgnoreFromConfigMap(cm)
 			if len(tc.expected) == 0 {
@@ -107,3 +109,62 @@ func TestGetTimeConfigFromConfigMap(t *testing.T) {
 	g.Expect(GetHoursToKeepFromConfigMap(cmWithoutValues)).To(BeEmpty())
 	g.Expect(GetDaysToKeepFromConfigMap(cmWithoutValues)).To(BeEmpty())
 }
+
+func TestParseKindToWatch(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected schema.GroupVersionKind
+	}{
+		{"Deployment", schema.GroupVersionKind{Kind: "Deployment"}},
+		{" Secret ", schema.GroupVersionKind{Kind: "Secret"}},
+		{"ConfigMap/v1", schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}},
+		{"Deployment.apps", schema.GroupVersionKind{Group: "apps", Kind: "Deployment"}},
+		{"Certificate.cert-manager.io/v1", schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ParseKindToWatch(tc.raw)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestResolveKindToWatch(t *testing.T) {
+	g := NewWithT(t)
+	appsV1 := schema.GroupVersion{Group: "apps", Version: "v1"}
+	certV1 := schema.GroupVersion{Group: "cert-manager.io", Version: "v1"}
+	coreV1 := schema.GroupVersion{Version: "v1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{coreV1, appsV1, certV1})
+	mapper.Add(coreV1.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(appsV1.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(certV1.WithKind("Certificate"), meta.RESTScopeNamespace)
+
+	testCases := []struct {
+		raw      string
+		expected schema.GroupVersionKind
+	}{
+		{"Deployment", appsV1.WithKind("Deployment")},
+		{"deployment", appsV1.WithKind("Deployment")},
+		{"Secret", coreV1.WithKind("Secret")},
+		{"Certificate.cert-manager.io", certV1.WithKind("Certificate")},
+		{"Certificate.cert-manager.io/v1", certV1.WithKind("Certificate")},
+		{"certificate.cert-manager.io/v1", certV1.WithKind("Certificate")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			g := NewWithT(t)
+			gvk, err := ResolveKindToWatch(mapper, tc.raw)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(gvk).To(Equal(tc.expected))
+		})
+	}
+
+	_, err := ResolveKindToWatch(mapper, "Application.argoproj.io/v1alpha1")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not served by the cluster"))
+
+	_, err = ResolveKindToWatch(mapper, ".apps/v1")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	"trashed-resources/internal/domain/trashedresources"
+	utils "trashed-resources/internal/utils"
+)
+
+var trashedresourcelog = logf.Log.WithName("trashedresource-resource")
+
+// SetupTrashedResourceWebhookWithManager registers the webhook for TrashedResource in the manager.
+// The conversion between v1alpha1 and v1alpha2 is served at /convert, v1alpha2 being the hub, and the
+// validation at /validate-mox-app-br-v1alpha2-trashedresource. config returns the configuration loaded
+// from the ConfigMap, with the groups allowed to shorten keepUntil.
+func SetupTrashedResourceWebhookWithManager(mgr ctrl.Manager, config func() utils.WatchConfig) error {
+	return ctrl.NewWebhookManagedBy(mgr, &moxv1alpha2.TrashedResource{}).
+		WithValidator(&TrashedResourceCustomValidator{Config: config}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-mox-app-br-v1alpha2-trashedresource,mutating=false,failurePolicy=fail,sideEffects=None,groups=mox.app.br,resources=trashedresources,verbs=create;update,versions=v1alpha2,name=vtrashedresource-v1alpha2.kb.io,admissionReviewVersions=v1
+
+// TrashedResourceCustomValidator makes the TrashedResources tamper-proof: the captured object and its
+// identity can not change after the creation and keepUntil can only be extended, except by the privileged
+// groups. The captured object must decode as it does at restore time.
+type TrashedResourceCustomValidator struct {
+	Config func() utils.WatchConfig
+}
+
+var _ admission.Validator[*moxv1alpha2.TrashedResource] = &TrashedResourceCustomValidator{}
+
+// ValidateCreate rejects the TrashedResources whose captured object can not be restored, and the ones whose
+// storage backend, Secrets or finalizers would make the controller reach what the user can not.
+func (v *TrashedResourceCustomValidator) ValidateCreate(_ context.Context,
+	trashed *moxv1alpha2.TrashedResource) (admission.Warnings, error) {
+	trashedresourcelog.Info("Validation for TrashedResource upon creation", "name", trashed.GetName())
+
+	errs := field.ErrorList{}
+	if err := trashedresources.ValidateData(trashed); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "data"), "<data>", err.Error()))
+	}
+	errs = append(errs, trashedresources.ValidateReferences(trashed, v.Config())...)
+	errs = append(errs, trashedresources.ValidateFinalizers(trashed)...)
+	if len(errs) > 0 {
+		return nil, invalid(trashed, errs)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate rejects the changes of the captured object, of its identity, the shorter keepUntil and the
+// mox.app.br/external-data finalizer without spec.external.
+func (v *TrashedResourceCustomValidator) ValidateUpdate(ctx context.Context,
+	oldTrashed, trashed *moxv1alpha2.TrashedResource) (admission.Warnings, error) {
+	trashedresourcelog.Info("Validation for TrashedResource upon update", "name", trashed.GetName())
+
+	spec, oldSpec := field.NewPath("spec"), oldTrashed.Spec
+	errs := field.ErrorList{}
+	immutable := func(path *field.Path, old, new any) {
+		if !equality.Semantic.DeepEqual(old, new) {
+			errs = append(errs, field.Forbidden(path, "field is immutable"))
+		}
+	}
+	immutable(spec.Child("data"), oldSpec.Data, trashed.Spec.Data)
+	immutable(spec.Child("encoding"), oldSpec.Encoding, trashed.Spec.Encoding)
+	immutable(spec.Child("external"), oldSpec.External, trashed.Spec.External)
+	immutable(spec.Child("encrypted"), oldSpec.Encrypted, trashed.Spec.Encrypted)
+	immutable(spec.Child("size"), oldSpec.Size, trashed.Spec.Size)
+	immutable(spec.Child("original"), oldSpec.Original, trashed.Spec.Original)
+	immutable(spec.Child("action"), oldSpec.Action, trashed.Spec.Action)
+	immutable(spec.Child("requestedBy"), oldSpec.RequestedBy, trashed.Spec.RequestedBy)
+	metadata := field.NewPath("metadata")
+	immutable(metadata.Child("annotations").Key("OriginalName"),
+		oldTrashed.Annotations["OriginalName"], trashed.Annotations["OriginalName"])
+	immutable(metadata.Child("labels").Key(moxv1alpha2.OriginalHashLabel),
+		oldTrashed.Labels[moxv1alpha2.OriginalHashLabel], trashed.Labels[moxv1alpha2.OriginalHashLabel])
+	immutable(metadata.Child("labels").Key(moxv1alpha2.CaptureReasonLabel),
+		oldTrashed.Labels[moxv1alpha2.CaptureReasonLabel], trashed.Labels[moxv1alpha2.CaptureReasonLabel])
+	immutable(metadata.Child("labels").Key(moxv1alpha2.OriginalNamespaceLabel),
+		oldTrashed.Labels[moxv1alpha2.OriginalNamespaceLabel], trashed.Labels[moxv1alpha2.OriginalNamespaceLabel])
+
+	var userInfo authenticationv1.UserInfo
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		userInfo = req.UserInfo
+	}
+	privileged := v.Config().IsPrivileged(userInfo.Groups)
+
+	// The data Secret can only be given up, leaving the object redacted, by the controller when it fails to
+	// create the Secret or by the privileged groups
+	downgraded := trashed.Spec.DataProtection == moxv1alpha2.DataRedacted && trashed.Spec.DataSecretRef == nil &&
+		(userInfo.Username == utils.ControllerServiceAccount || privileged)
+	if !downgraded {
+		immutable(spec.Child("dataProtection"), oldSpec.DataProtection, trashed.Spec.DataProtection)
+		immutable(spec.Child("dataSecretRef"), oldSpec.DataSecretRef, trashed.Spec.DataSecretRef)
+	}
+
+	if trashed.Spec.KeepUntil.Before(&oldSpec.KeepUntil) {
+		if !privileged {
+			errs = append(errs, field.Forbidden(spec.Child("keepUntil"), fmt.Sprintf(
+				"can only be extended, it can not be earlier than %s", oldSpec.KeepUntil.UTC().Format(time.RFC3339))))
+		}
+	}
+
+	errs = append(errs, trashedresources.ValidateFinalizers(trashed)...)
+
+	if len(errs) > 0 {
+		return nil, invalid(trashed, errs)
+	}
+	return nil, nil
+}
+
+// ValidateDelete allows every deletion, the retention is enforced by the controller.
+func (v *TrashedResourceCustomValidator) ValidateDelete(_ context.Context,
+	_ *moxv1alpha2.TrashedResource) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func invalid(trashed *moxv1alpha2.TrashedResource, errs field.ErrorList) error {
+	return apierrors.NewInvalid(moxv1alpha2.GroupVersion.WithKind("TrashedResource").GroupKind(), trashed.Name, errs)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	moxv1alpha2 "trashed-resources/api/v1alpha2"
+	utils "trashed-resources/internal/utils"
+)
+
+func newValidator() *TrashedResourceCustomValidator {
+	return &TrashedResourceCustomValidator{Config: func() utils.WatchConfig {
+		return utils.WatchConfig{
+			PrivilegedGroups:    []string{"trash-admins"},
+			StorageBackend:      "file:///trash",
+			EncryptionKeySecret: "trashed-resources-key",
+		}
+	}}
+}
+
+func newTrashedResource() *moxv1alpha2.TrashedResource {
+	return &moxv1alpha2.TrashedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "trashed",
+			Namespace:   "default",
+			Annotations: map[string]string{"OriginalName": "cm"},
+			Labels:      map[string]string{moxv1alpha2.OriginalHashLabel: "hash"},
+		},
+		Spec: moxv1alpha2.TrashedResourceSpec{
+			Data:      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			KeepUntil: metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second)),
+			Original:  moxv1alpha2.OriginalReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm"},
+			Action:    moxv1alpha2.ActionDelete,
+		},
+	}
+}
+
+func contextWithGroups(groups ...string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Groups: groups}},
+	})
+}
+
+func contextWithUser(username string, groups ...string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups}},
+	})
+}
+
+func TestValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+	validator := newValidator()
+
+	_, err := validator.ValidateCreate(context.Background(), newTrashedResource())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	malformed := newTrashedResource()
+	malformed.Spec.Data = "not: a kubernetes object"
+	_, err = validator.ValidateCreate(context.Background(), malformed)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+}
+
+func TestValidateCreate_References(t *testing.T) {
+	g := NewWithT(t)
+	validator := newValidator()
+	external := func(url, key, encryptedKey string) func(*moxv1alpha2.TrashedResource) {
+		return func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Spec.Data = ""
+			trashed.Spec.External = &moxv1alpha2.ExternalData{URL: url, Key: key, EncryptedKey: encryptedKey}
+			trashed.Finalizers = []string{moxv1alpha2.ExternalDataFinalizer}
+		}
+	}
+	encrypted := func(ref moxv1alpha2.SecretKeyReference) func(*moxv1alpha2.TrashedResource) {
+		return func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Spec.DataProtection = moxv1alpha2.DataEncrypted
+			trashed.Spec.Encrypted = &moxv1alpha2.EncryptedData{KeyRef: ref, Key: "key", Data: "data"}
+		}
+	}
+	controllerKey := moxv1alpha2.SecretKeyReference{Namespace: utils.ControllerNamespace,
+		Name: "trashed-resources-key", Key: utils.EncryptionKeySecretKey}
+
+	for name, change := range map[string]func(*moxv1alpha2.TrashedResource){
+		"external":      external("file:///trash", "default/trashed", "default/trashed.encrypted"),
+		"encryptionKey": encrypted(controllerKey),
+		"namespaceKey":  encrypted(moxv1alpha2.SecretKeyReference{Name: "key", Key: "key"}),
+		"namespaceDataRef": func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Spec.DataSecretRef = &moxv1alpha2.SecretKeyReference{Namespace: "default", Name: "data", Key: "object"}
+		},
+	} {
+		trashed := newTrashedResource()
+		change(trashed)
+		_, err := validator.ValidateCreate(context.Background(), trashed)
+		g.Expect(err).NotTo(HaveOccurred(), name)
+	}
+
+	otherKey := controllerKey
+	otherKey.Name = "other-key"
+	for name, change := range map[string]func(*moxv1alpha2.TrashedResource){
+		"url":          external("file:///var/run/secrets/kubernetes.io/serviceaccount", "default/trashed", ""),
+		"key":          external("file:///trash", "team-b/trashed-other", ""),
+		"encryptedKey": external("file:///trash", "default/trashed", "team-b/trashed-other.encrypted"),
+		"keyRef":       encrypted(otherKey),
+		"dataSecretRef": func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Spec.DataSecretRef = &moxv1alpha2.SecretKeyReference{Namespace: "team-b", Name: "data", Key: "object"}
+		},
+		"finalizer": func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Finalizers = []string{moxv1alpha2.ExternalDataFinalizer}
+		},
+	} {
+		trashed := newTrashedResource()
+		change(trashed)
+		_, err := validator.ValidateCreate(context.Background(), trashed)
+		g.Expect(apierrors.IsInvalid(err)).To(BeTrue(), name)
+	}
+}
+
+func TestValidateUpdate_Immutable(t *testing.T) {
+	g := NewWithT(t)
+	validator := newValidator()
+	old := newTrashedResource()
+
+	for name, change := range map[string]func(*moxv1alpha2.TrashedResource){
+		"data":         func(trashed *moxv1alpha2.TrashedResource) { trashed.Spec.Data += "data:\n  key: value\n" },
+		"encoding":     func(trashed *moxv1alpha2.TrashedResource) { trashed.Spec.Encoding = moxv1alpha2.DataEncodingGzip },
+		"original":     func(trashed *moxv1alpha2.TrashedResource) { trashed.Spec.Original.Name = "other" },
+		"action":       func(trashed *moxv1alpha2.TrashedResource) { trashed.Spec.Action = moxv1alpha2.ActionUpdate },
+		"size":         func(trashed *moxv1alpha2.TrashedResource) { trashed.Spec.Size = &moxv1alpha2.DataSize{Original: 1} },
+		"requestedBy":  func(trashed *moxv1alpha2.TrashedResource) { trashed.Spec.RequestedBy = "someone" },
+		"OriginalName": func(trashed *moxv1alpha2.TrashedResource) { trashed.Annotations["OriginalName"] = "other" },
+		"hash":         func(trashed *moxv1alpha2.TrashedResource) { delete(trashed.Labels, moxv1alpha2.OriginalHashLabel) },
+		"reason": func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Labels[moxv1alpha2.CaptureReasonLabel] = moxv1alpha2.CaptureReasonDetectedOffline
+		},
+		"originalNamespace": func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Labels[moxv1alpha2.OriginalNamespaceLabel] = "other"
+		},
+		"protection": func(trashed *moxv1alpha2.TrashedResource) {
+			trashed.Spec.DataProtection = moxv1alpha2.DataEncrypted
+		},
+	} {
+		trashed := newTrashedResource()
+		change(trashed)
+		_, err := validator.ValidateUpdate(contextWithGroups("system:masters"), old, trashed)
+		g.Expect(apierrors.IsInvalid(err)).To(BeTrue(), name)
+	}
+
+	// Labels, annotations and status other than the identity of the original object can change
+	trashed := newTrashedResource()
+	trashed.Labels["team"] = "a"
+	trashed.Annotations["note"] = "keep"
+	trashed.Status.Phase = moxv1alpha2.PhaseRestored
+	_, err := validator.ValidateUpdate(context.Background(), old, trashed)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestValidateUpdate_DataSecretDowngrade(t *testing.T) {
+	g := NewWithT(t)
+	validator := newValidator()
+	old := newTrashedResource()
+	old.Spec.DataProtection = moxv1alpha2.DataSecret
+	old.Spec.DataSecretRef = &moxv1alpha2.SecretKeyReference{Name: "data", Key: "object"}
+
+	redacted := old.DeepCopy()
+	redacted.Spec.DataProtection = moxv1alpha2.DataRedacted
+	redacted.Spec.DataSecretRef = nil
+	_, err := validator.ValidateUpdate(contextWithUser(utils.ControllerServiceAccount), old, redacted)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = validator.ValidateUpdate(contextWithGroups("trash-admins"), old, redacted)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// An editor of the TrashedResources can not drop the reference to the data Secret
+	_, err = validator.ValidateUpdate(contextWithUser("jane@example.com", "editors"), old, redacted)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.dataSecretRef"))
+	_, err = validator.ValidateUpdate(context.Background(), old, redacted)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+	moved := old.DeepCopy()
+	moved.Spec.DataSecretRef = &moxv1alpha2.SecretKeyReference{Name: "other", Key: "object"}
+	_, err = validator.ValidateUpdate(context.Background(), old, moved)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+}
+
+func TestValidateUpdate_ExternalDataFinalizer(t *testing.T) {
+	g := NewWithT(t)
+	validator := newValidator()
+	old := newTrashedResource()
+
+	trashed := newTrashedResource()
+	trashed.Finalizers = []string{moxv1alpha2.ExternalDataFinalizer}
+	_, err := validator.ValidateUpdate(contextWithGroups("trash-admins"), old, trashed)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("metadata.finalizers"))
+}
+
+func TestValidateUpdate_KeepUntil(t *testing.T) {
+	g := NewWithT(t)
+	validator := newValidator()
+	old := newTrashedResource()
+
+	extended := newTrashedResource()
+	extended.Spec.KeepUntil = metav1.NewTime(old.Spec.KeepUntil.Add(time.Hour))
+	_, err := validator.ValidateUpdate(contextWithGroups("developers"), old, extended)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	shortened := newTrashedResource()
+	shortened.Spec.KeepUntil = metav1.NewTime(old.Spec.KeepUntil.Add(-time.Minute))
+	_, err = validator.ValidateUpdate(contextWithGroups("developers"), old, shortened)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.keepUntil"))
+	_, err = validator.ValidateUpdate(context.Background(), old, shortened)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+	_, err = validator.ValidateUpdate(contextWithGroups("developers", "trash-admins"), old, shortened)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = validator.ValidateUpdate(contextWithGroups(utils.DefaultPrivilegedGroup), old, shortened)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestValidateDelete(t *testing.T) {
+	g := NewWithT(t)
+	_, err := newValidator().ValidateDelete(context.Background(), newTrashedResource())
+	g.Expect(err).NotTo(HaveOccurred())
+}